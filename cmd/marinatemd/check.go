@@ -0,0 +1,358 @@
+package marinatemd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/glueckkanja/marinatemd/internal/config"
+	"github.com/glueckkanja/marinatemd/internal/diff"
+	"github.com/glueckkanja/marinatemd/internal/hclparse"
+	"github.com/glueckkanja/marinatemd/internal/logger"
+	"github.com/glueckkanja/marinatemd/internal/markdown"
+	"github.com/glueckkanja/marinatemd/internal/paths"
+	"github.com/glueckkanja/marinatemd/internal/schema"
+	"github.com/glueckkanja/marinatemd/internal/yamlio"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkInjectType string
+	checkRecursive  bool
+)
+
+// errStaleDocs is returned when generated files differ from what export and inject would produce.
+var errStaleDocs = errors.New("documentation is out of date")
+
+// checkCmd represents the check command that verifies generated documentation is up to date.
+var checkCmd = &cobra.Command{
+	Use:   "check [module-path]",
+	Short: "Verify that YAML schemas and injected documentation are up to date",
+	Long: `Run export and inject in memory and compare the results with the files on disk,
+without writing anything. Intended as a CI gate that fails when someone changes a
+variable without re-running export and inject.
+
+This command:
+  1. Builds the YAML schema for every MARINATED variable and merges it with the
+     existing file, exactly like export
+  2. Compares the result with the YAML files in the export directory
+  3. Renders each schema and compares it with the MARINATED blocks in the
+     docs_file and/or Terraform files (selected by --inject-type)
+  4. Prints a unified diff for every stale variable and exits non-zero
+
+Example:
+  marinatemd check .
+  marinatemd check --inject-type both .
+  marinatemd check --recursive .`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+
+	checkCmd.Flags().StringVar(
+		&checkInjectType,
+		"inject-type",
+		"markdown",
+		"injected documentation to check: markdown, terraform, or both",
+	)
+
+	checkCmd.Flags().BoolVarP(
+		&checkRecursive,
+		"recursive",
+		"r",
+		false,
+		"check every module below module-path that contains MARINATED variables",
+	)
+}
+
+// staleFile is a file whose content differs from what export or inject would write for a variable.
+type staleFile struct {
+	variable string
+	diff     string
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	if err := validateInjectTypeValue(checkInjectType); err != nil {
+		return err
+	}
+
+	// Diff headers use paths relative to the working directory so they stay
+	// unambiguous when several modules are checked at once.
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	run := func(moduleRoot string, cfg *config.Config) error {
+		return checkModule(out, cwd, moduleRoot, cfg)
+	}
+
+	if checkRecursive {
+		return runRecursive(cmd, args, "check", run)
+	}
+
+	moduleRoot, cfg, err := paths.SetupEnvironment(args)
+	if err != nil {
+		return err
+	}
+
+	if checkErr := run(moduleRoot, cfg); checkErr != nil {
+		if errors.Is(checkErr, errStaleDocs) {
+			// The diff already explains what went wrong; usage text would only add noise.
+			cmd.SilenceUsage = true
+		}
+		return checkErr
+	}
+
+	return nil
+}
+
+// checkModule checks the YAML schemas and injected documentation of a single module
+// and writes a unified diff for every stale file to out. File names in the diffs are relative to base.
+func checkModule(out io.Writer, base, moduleRoot string, cfg *config.Config) error {
+	exportPath := paths.ResolveExportPath(moduleRoot, cfg)
+
+	logger.Log.Info("checking documentation", "moduleRoot", moduleRoot, "exportPath", exportPath)
+
+	marinatedVars, err := parseAndExtractVariables(moduleRoot, cfg.TerraformFiles)
+	if err != nil {
+		return err
+	}
+
+	schemas, stale, err := checkSchemas(marinatedVars, newSchemaBuilder(cfg, nil), base, exportPath)
+	if err != nil {
+		return err
+	}
+
+	renderer := markdown.NewRendererWithTemplate(cfg.MarkdownTemplate)
+	expected := &expectedSchemas{schemas: schemas, reader: yamlio.NewReader(exportPath)}
+
+	if checkInjectType == injectTypeMarkdown || checkInjectType == injectTypeBoth {
+		mdStale, mdErr := checkMarkdown(resolveDefaultDocsFile(moduleRoot, cfg), base, expected, renderer)
+		if mdErr != nil {
+			return mdErr
+		}
+		stale = append(stale, mdStale...)
+	}
+
+	if checkInjectType == injectTypeTerraform || checkInjectType == injectTypeBoth {
+		tfInjector := hclparse.NewTerraformInjectorWithFilePatterns(moduleRoot, cfg.TerraformFiles)
+		tfRenderer := markdown.NewTerraformRenderer(cfg.MarkdownTemplate)
+		tfStale, tfErr := checkTerraform(tfInjector, base, expected, tfRenderer)
+		if tfErr != nil {
+			return tfErr
+		}
+		stale = append(stale, tfStale...)
+	}
+
+	return printCheckSummary(out, stale)
+}
+
+// checkSchemas builds and merges the schema of every variable in memory and compares it
+// with the YAML file on disk. Returns the merged schemas keyed by MARINATED ID.
+func checkSchemas(
+	marinatedVars []*hclparse.Variable,
+	builder *schema.Builder,
+	base, exportPath string,
+) (map[string]*schema.Schema, []staleFile, error) {
+	reader := yamlio.NewReader(exportPath)
+	writer := yamlio.NewWriter(exportPath)
+
+	schemas := make(map[string]*schema.Schema, len(marinatedVars))
+	var stale []staleFile
+
+	for _, variable := range marinatedVars {
+		finalSchema, _, err := buildMergedSchema(variable, builder, reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		schemas[variable.MarinatedID] = finalSchema
+
+		want, err := writer.RenderSchema(finalSchema)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode schema for %s: %w", variable.MarinatedID, err)
+		}
+
+		yamlPath := reader.SchemaPath(variable.MarinatedID)
+		fromName := relativeTo(base, yamlPath)
+		got, err := os.ReadFile(yamlPath)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			fromName = "/dev/null"
+		case err != nil:
+			return nil, nil, fmt.Errorf("failed to read schema file %s: %w", yamlPath, err)
+		}
+
+		if d := diff.Unified(fromName, relativeTo(base, yamlPath), string(got), string(want)); d != "" {
+			logger.Log.Debug("schema is stale", "variable", variable.MarinatedID, "path", yamlPath)
+			stale = append(stale, staleFile{variable: variable.MarinatedID, diff: d})
+		}
+	}
+
+	return schemas, stale, nil
+}
+
+// expectedSchemas resolves the schema inject would render for a marker: the merged schema
+// from the in-memory export, or the YAML file on disk for markers export does not produce.
+type expectedSchemas struct {
+	schemas map[string]*schema.Schema
+	reader  *yamlio.Reader
+}
+
+// render renders the expected schema for a marker.
+// Returns false if inject would skip the marker because no schema exists.
+func (e *expectedSchemas) render(markerID string, renderer *markdown.Renderer) (string, bool, error) {
+	s, ok := e.schemas[markerID]
+	if !ok {
+		var err error
+		if s, err = e.reader.ReadSchema(markerID); err != nil {
+			return "", false, fmt.Errorf("failed to read schema for %s: %w", markerID, err)
+		}
+	}
+	if s == nil {
+		logger.Log.Warn("no schema found, marker is not checked", "marker", markerID)
+		return "", false, nil
+	}
+
+	rendered, err := renderer.RenderSchema(s)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to render markdown for %s: %w", markerID, err)
+	}
+	return rendered, true, nil
+}
+
+// checkMarkdown compares the MARINATED blocks in the markdown file with freshly rendered schemas.
+func checkMarkdown(
+	markdownPath, base string,
+	expected *expectedSchemas,
+	renderer *markdown.Renderer,
+) ([]staleFile, error) {
+	content, err := os.ReadFile(markdownPath)
+	if err != nil {
+		return nil, fmt.Errorf("markdown file not found: %s", markdownPath)
+	}
+
+	injector := markdown.NewInjector()
+	markers, err := injector.FindMarkers(markdownPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find markers in documentation file: %w", err)
+	}
+
+	var stale []staleFile
+	current := string(content)
+	name := relativeTo(base, markdownPath)
+
+	for _, markerID := range markers {
+		rendered, ok, renderErr := expected.render(markerID, renderer)
+		if renderErr != nil {
+			return nil, renderErr
+		}
+		if !ok {
+			continue
+		}
+
+		updated, injectErr := injector.Inject(current, markerID, rendered)
+		if injectErr != nil {
+			logger.Log.Warn("could not check markdown", "marker", markerID, "error", injectErr)
+			continue
+		}
+
+		// Each diff is taken against the already-updated content, so it only shows this variable's block.
+		if d := diff.Unified(name, name, current, updated); d != "" {
+			logger.Log.Debug("markdown is stale", "variable", markerID, "path", markdownPath)
+			stale = append(stale, staleFile{variable: markerID, diff: d})
+		}
+		current = updated
+	}
+
+	return stale, nil
+}
+
+// checkTerraform compares the documentation injected into Terraform variable descriptions
+// with freshly rendered schemas.
+func checkTerraform(
+	tfInjector *hclparse.TerraformInjector,
+	base string,
+	expected *expectedSchemas,
+	renderer *markdown.Renderer,
+) ([]staleFile, error) {
+	markers, err := tfInjector.FindMarkers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find markers in Terraform files: %w", err)
+	}
+
+	var stale []staleFile
+	contents := make(map[string]string)
+
+	for _, markerID := range markers {
+		filePath, _, findErr := tfInjector.FindVariableFile(markerID)
+		if findErr != nil {
+			logger.Log.Warn("could not find variable file", "marker", markerID, "error", findErr)
+			continue
+		}
+
+		current, ok := contents[filePath]
+		if !ok {
+			content, readErr := os.ReadFile(filePath)
+			if readErr != nil {
+				return nil, fmt.Errorf("failed to read Terraform file %s: %w", filePath, readErr)
+			}
+			current = string(content)
+		}
+
+		rendered, found, renderErr := expected.render(markerID, renderer)
+		if renderErr != nil {
+			return nil, renderErr
+		}
+		if !found {
+			continue
+		}
+
+		updated, injectErr := tfInjector.Inject(current, markerID, rendered)
+		if injectErr != nil {
+			logger.Log.Warn("could not check Terraform file", "marker", markerID, "error", injectErr)
+			continue
+		}
+
+		name := relativeTo(base, filePath)
+		if d := diff.Unified(name, name, current, updated); d != "" {
+			logger.Log.Debug("Terraform documentation is stale", "variable", markerID, "path", filePath)
+			stale = append(stale, staleFile{variable: markerID, diff: d})
+		}
+		contents[filePath] = updated
+	}
+
+	return stale, nil
+}
+
+// printCheckSummary writes the diffs of all stale files and returns errStaleDocs if there are any.
+func printCheckSummary(out io.Writer, stale []staleFile) error {
+	if len(stale) == 0 {
+		logger.Log.Info("documentation is up to date")
+		return nil
+	}
+
+	variables := make(map[string]bool)
+	for _, file := range stale {
+		variables[file.variable] = true
+		fmt.Fprint(out, file.diff)
+	}
+
+	return fmt.Errorf("%w: %d stale files for %d variables (run 'marinatemd export' and 'marinatemd inject')",
+		errStaleDocs, len(stale), len(variables))
+}
+
+// relativeTo returns path relative to root, or path itself if no relative path exists.
+func relativeTo(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
@@ -0,0 +1,231 @@
+package marinatemd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/glueckkanja/marinatemd/internal/config"
+	"github.com/glueckkanja/marinatemd/internal/logger"
+	"github.com/glueckkanja/marinatemd/internal/paths"
+	"github.com/glueckkanja/marinatemd/internal/schema"
+	"github.com/glueckkanja/marinatemd/internal/yamlio"
+	"github.com/spf13/cobra"
+)
+
+const (
+	coverageFormatText = "text"
+	coverageFormatJSON = "json"
+)
+
+var (
+	coverageMinimum   float64
+	coverageFormat    string
+	coverageRecursive bool
+)
+
+// coverageCmd represents the coverage command that reports TODO placeholder descriptions.
+var coverageCmd = &cobra.Command{
+	Use:     "coverage [module-path]",
+	Aliases: []string{"lint"},
+	Short:   "Report documentation coverage of the YAML schemas",
+	Long: `Walk every YAML schema in the export directory and report how many attributes
+have a real description instead of a "# TODO: Add description for ..." placeholder.
+
+Coverage is reported per variable and per module, together with the dotted path of
+every undocumented attribute. Attributes with show_description: false are
+intentionally undocumented and are not counted. Attributes marked needs_review by
+export, because their type, required flag or default changed under an existing
+description, are listed until the needs_review flag is removed.
+
+Flags:
+  --min-coverage   Fail if the coverage of any module is below this percentage (0-100)
+  --format         Output format: "text" (default) or "json"
+  --recursive      Report every module below module-path that contains MARINATED variables
+
+Example:
+  marinatemd coverage .
+  marinatemd coverage --min-coverage 80 .
+  marinatemd lint --format json --recursive .`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCoverage,
+}
+
+func init() {
+	rootCmd.AddCommand(coverageCmd)
+
+	coverageCmd.Flags().Float64Var(
+		&coverageMinimum,
+		"min-coverage",
+		0,
+		"fail if the coverage of any module is below this percentage",
+	)
+
+	coverageCmd.Flags().StringVar(
+		&coverageFormat,
+		"format",
+		coverageFormatText,
+		"output format: text or json",
+	)
+
+	coverageCmd.Flags().BoolVarP(
+		&coverageRecursive,
+		"recursive",
+		"r",
+		false,
+		"report every module below module-path that contains MARINATED variables",
+	)
+}
+
+// moduleCoverage is the documentation coverage of all schemas of one module.
+type moduleCoverage struct {
+	Path       string             `json:"path"` // Relative to the path the command was run on
+	Total      int                `json:"total"`
+	Documented int                `json:"documented"`
+	Percent    float64            `json:"coverage"`
+	Variables  []*schema.Coverage `json:"variables"`
+}
+
+// coverageReport is the documentation coverage across all reported modules.
+type coverageReport struct {
+	Total      int               `json:"total"`
+	Documented int               `json:"documented"`
+	Percent    float64           `json:"coverage"`
+	Modules    []*moduleCoverage `json:"modules"`
+}
+
+func runCoverage(cmd *cobra.Command, args []string) error {
+	if coverageFormat != coverageFormatText && coverageFormat != coverageFormatJSON {
+		return fmt.Errorf("invalid format: %s (must be text or json)", coverageFormat)
+	}
+	if coverageMinimum < 0 || coverageMinimum > 100 {
+		return fmt.Errorf("invalid min-coverage: %g (must be between 0 and 100)", coverageMinimum)
+	}
+
+	root, cfg, err := paths.SetupEnvironment(args)
+	if err != nil {
+		return err
+	}
+
+	modules := []paths.Module{{Root: root, Config: cfg}}
+	if coverageRecursive {
+		if modules, err = paths.DiscoverModules(root, cfg); err != nil {
+			return err
+		}
+	}
+
+	report := &coverageReport{Modules: make([]*moduleCoverage, 0, len(modules))}
+	for _, module := range modules {
+		if module.Err != nil {
+			return fmt.Errorf("failed to load module %s: %w", module.Root, module.Err)
+		}
+
+		mc, moduleErr := computeModuleCoverage(relativeTo(root, module.Root), module.Root, module.Config)
+		if moduleErr != nil {
+			return moduleErr
+		}
+		report.Modules = append(report.Modules, mc)
+		report.Total += mc.Total
+		report.Documented += mc.Documented
+	}
+	report.Percent = schema.CoveragePercent(report.Documented, report.Total)
+
+	if printErr := printCoverageReport(cmd.OutOrStdout(), report); printErr != nil {
+		return printErr
+	}
+
+	if minErr := checkMinimumCoverage(report); minErr != nil {
+		// The report already shows what is missing; usage text would only add noise.
+		cmd.SilenceUsage = true
+		return minErr
+	}
+
+	return nil
+}
+
+// computeModuleCoverage reads every schema in the module's export directory and computes its coverage.
+func computeModuleCoverage(name, moduleRoot string, cfg *config.Config) (*moduleCoverage, error) {
+	exportPath := paths.ResolveExportPath(moduleRoot, cfg)
+	reader := yamlio.NewReader(exportPath)
+
+	variables, err := reader.ListSchemas()
+	if err != nil {
+		return nil, err
+	}
+	if len(variables) == 0 {
+		logger.Log.Warn("no YAML schemas found",
+			"path", exportPath,
+			"help", "Run 'marinatemd export' first to generate YAML schemas")
+	}
+
+	mc := &moduleCoverage{Path: name, Variables: make([]*schema.Coverage, 0, len(variables))}
+	for _, variable := range variables {
+		s, readErr := reader.ReadSchema(variable)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read schema for %s: %w", variable, readErr)
+		}
+		if s.Variable == "" {
+			s.Variable = variable
+		}
+
+		c := schema.ComputeCoverage(s)
+		mc.Variables = append(mc.Variables, c)
+		mc.Total += c.Total
+		mc.Documented += c.Documented
+	}
+	mc.Percent = schema.CoveragePercent(mc.Documented, mc.Total)
+
+	return mc, nil
+}
+
+// printCoverageReport writes the report in the selected format.
+func printCoverageReport(out io.Writer, report *coverageReport) error {
+	if coverageFormat == coverageFormatJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode coverage report: %w", err)
+		}
+		return nil
+	}
+
+	for _, module := range report.Modules {
+		fmt.Fprintf(out, "module %s: %s\n", module.Path, formatCoverage(module.Percent, module.Documented, module.Total))
+		for _, c := range module.Variables {
+			fmt.Fprintf(out, "  %s: %s\n", c.Variable, formatCoverage(c.Percent, c.Documented, c.Total))
+			for _, path := range c.Undocumented {
+				fmt.Fprintf(out, "    - %s\n", path)
+			}
+			for _, path := range c.NeedsReview {
+				fmt.Fprintf(out, "    - %s (needs review)\n", path)
+			}
+		}
+	}
+	fmt.Fprintf(out, "total: %s\n", formatCoverage(report.Percent, report.Documented, report.Total))
+
+	return nil
+}
+
+func formatCoverage(percent float64, documented, total int) string {
+	return fmt.Sprintf("%.1f%% (%d/%d documented)", percent, documented, total)
+}
+
+// checkMinimumCoverage returns an error listing every module below --min-coverage.
+func checkMinimumCoverage(report *coverageReport) error {
+	if coverageMinimum == 0 {
+		return nil
+	}
+
+	var below []string
+	for _, module := range report.Modules {
+		if module.Percent < coverageMinimum {
+			below = append(below, fmt.Sprintf("%s (%.1f%%)", module.Path, module.Percent))
+		}
+	}
+	if len(below) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("documentation coverage is below %g%% in %s", coverageMinimum, strings.Join(below, ", "))
+}
@@ -0,0 +1,261 @@
+package marinatemd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/glueckkanja/marinatemd/internal/config"
+	"github.com/glueckkanja/marinatemd/internal/hclparse"
+	"github.com/glueckkanja/marinatemd/internal/logger"
+	"github.com/glueckkanja/marinatemd/internal/markdown"
+	"github.com/glueckkanja/marinatemd/internal/paths"
+	"github.com/glueckkanja/marinatemd/internal/schema"
+	"github.com/glueckkanja/marinatemd/internal/yamlio"
+	"github.com/spf13/cobra"
+)
+
+// Example modes.
+const (
+	exampleModeMinimal = "minimal"
+	exampleModeFull    = "full"
+)
+
+var (
+	exampleMode       string
+	exampleFormat     string
+	exampleOutput     string
+	exampleInject     bool
+	exampleModuleName string
+	exampleSource     string
+	exampleRecursive  bool
+)
+
+// exampleCmd represents the example command that generates module calls and tfvars files from schemas.
+var exampleCmd = &cobra.Command{
+	Use:   "example [module-path]",
+	Short: "Generate an example module call or tfvars file from the schemas",
+	Long: `Generate a complete HCL example for the module's MARINATED variables, either as a
+module block or as a tfvars file.
+
+Every attribute gets its example from the YAML schema, its default if it has no
+example, or a placeholder of its type ("<name>", 0, false) otherwise. Lists, sets
+and maps get a single element.
+
+Modes:
+  minimal   Only variables without a default and only required attributes
+  full      Every MARINATED variable and every attribute
+
+The example is written to stdout, to --output, or, with --inject, into the
+docs_file at <!-- MARINATED-EXAMPLE: minimal --> and <!-- MARINATED-EXAMPLE: full -->
+markers as an HCL code block. Each marker selects its own mode.
+
+Flags:
+  --mode          Example mode: "minimal" (default) or "full"
+  --format        Output format: "module" (default) or "tfvars"
+  --output        Write the example to this file (relative to the module)
+  --inject        Inject examples into the MARINATED-EXAMPLE markers of docs_file
+  --module-name   Name of the module block (defaults to the module directory name)
+  --source        Source of the module block (defaults to the module path)
+  --recursive     Generate examples for every module below module-path
+
+Example:
+  marinatemd example .
+  marinatemd example --mode full --format tfvars --output terraform.tfvars.example .
+  marinatemd example --inject --source "git::https://example.com/modules/app.git" .`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExample,
+}
+
+func init() {
+	rootCmd.AddCommand(exampleCmd)
+
+	exampleCmd.Flags().StringVar(&exampleMode, "mode", exampleModeMinimal, "example mode: minimal or full")
+	exampleCmd.Flags().StringVar(&exampleFormat, "format", markdown.ExampleFormatModule, "output format: module or tfvars")
+	exampleCmd.Flags().StringVarP(&exampleOutput, "output", "o", "", "write the example to this file (relative to the module)")
+	exampleCmd.Flags().BoolVar(&exampleInject, "inject", false, "inject examples into the MARINATED-EXAMPLE markers of docs_file")
+	exampleCmd.Flags().StringVar(&exampleModuleName, "module-name", "", "name of the module block (defaults to the module directory name)")
+	exampleCmd.Flags().StringVar(&exampleSource, "source", "", "source of the module block (defaults to the module path)")
+
+	exampleCmd.Flags().BoolVarP(
+		&exampleRecursive,
+		"recursive",
+		"r",
+		false,
+		"generate examples for every module below module-path that contains MARINATED variables",
+	)
+}
+
+func runExample(cmd *cobra.Command, args []string) error {
+	if err := validateExampleFlags(); err != nil {
+		return err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	run := func(moduleRoot string, cfg *config.Config) error {
+		return exampleModule(out, cwd, moduleRoot, cfg)
+	}
+
+	if exampleRecursive {
+		return runRecursive(cmd, args, "example", run)
+	}
+
+	moduleRoot, cfg, err := paths.SetupEnvironment(args)
+	if err != nil {
+		return err
+	}
+
+	return run(moduleRoot, cfg)
+}
+
+func validateExampleFlags() error {
+	if err := validateExampleMode(exampleMode); err != nil {
+		return err
+	}
+	if exampleFormat != markdown.ExampleFormatModule && exampleFormat != markdown.ExampleFormatTfvars {
+		return fmt.Errorf("invalid format: %s (must be %q or %q)",
+			exampleFormat, markdown.ExampleFormatModule, markdown.ExampleFormatTfvars)
+	}
+	if exampleInject && exampleOutput != "" {
+		return fmt.Errorf("--inject cannot be combined with --output")
+	}
+	return nil
+}
+
+func validateExampleMode(mode string) error {
+	if mode != exampleModeMinimal && mode != exampleModeFull {
+		return fmt.Errorf("invalid example mode: %s (must be %q or %q)", mode, exampleModeMinimal, exampleModeFull)
+	}
+	return nil
+}
+
+// exampleModule generates the example of a single module and writes it to out, to --output,
+// or into the markers of the module's docs_file.
+func exampleModule(out io.Writer, cwd, moduleRoot string, cfg *config.Config) error {
+	exportPath := paths.ResolveExportPath(moduleRoot, cfg)
+
+	marinatedVars, err := parseAndExtractVariables(moduleRoot, cfg.TerraformFiles)
+	if err != nil {
+		return err
+	}
+
+	builder := newSchemaBuilder(cfg, nil)
+	reader := yamlio.NewReader(exportPath)
+	schemas := make([]*schema.Schema, len(marinatedVars))
+	for i, variable := range marinatedVars {
+		if schemas[i], _, err = buildMergedSchema(variable, builder, reader); err != nil {
+			return err
+		}
+	}
+
+	render := func(mode string) string {
+		return renderExample(marinatedVars, schemas, mode, exampleCallName(moduleRoot), exampleCallSource(cwd, moduleRoot))
+	}
+
+	switch {
+	case exampleInject:
+		return injectExamples(resolveDefaultDocsFile(moduleRoot, cfg), render)
+	case exampleOutput != "":
+		path := exampleOutput
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(moduleRoot, path)
+		}
+		if writeErr := os.WriteFile(path, []byte(render(exampleMode)), 0600); writeErr != nil {
+			return fmt.Errorf("failed to write example %s: %w", path, writeErr)
+		}
+		logger.Log.Info("example written", "path", path, "mode", exampleMode, "format", exampleFormat)
+		return nil
+	default:
+		fmt.Fprint(out, render(exampleMode))
+		return nil
+	}
+}
+
+// renderExample renders the example of the given variables. In minimal mode, variables
+// with a default are left out.
+func renderExample(variables []*hclparse.Variable, schemas []*schema.Schema, mode, name, source string) string {
+	full := mode == exampleModeFull
+
+	inputs := make([]markdown.ExampleInput, 0, len(variables))
+	for i, variable := range variables {
+		if !full && variable.HasDefault {
+			continue
+		}
+		inputs = append(inputs, markdown.ExampleInput{Name: variable.Name, Value: schema.ExampleValue(schemas[i], full)})
+	}
+
+	if exampleFormat == markdown.ExampleFormatTfvars {
+		return markdown.RenderTfvars(inputs)
+	}
+	return markdown.RenderModuleCall(name, source, inputs)
+}
+
+// injectExamples renders an HCL code block for every MARINATED-EXAMPLE marker in the
+// documentation file, in the mode the marker names, and writes the file back.
+func injectExamples(docsPath string, render func(mode string) string) error {
+	injector := markdown.NewInjector()
+	markers, err := injector.FindExampleMarkers(docsPath)
+	if err != nil {
+		return fmt.Errorf("failed to find example markers: %w", err)
+	}
+	if len(markers) == 0 {
+		logger.Log.Warn("no example markers found",
+			"path", docsPath,
+			"help", "Add <!-- MARINATED-EXAMPLE: minimal --> or <!-- MARINATED-EXAMPLE: full --> to the file")
+		return nil
+	}
+
+	content, err := os.ReadFile(docsPath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	updated := string(content)
+	for _, mode := range markers {
+		if modeErr := validateExampleMode(mode); modeErr != nil {
+			return fmt.Errorf("invalid example marker in %s: %w", docsPath, modeErr)
+		}
+		block := "```hcl\n" + render(mode) + "```"
+		if updated, err = injector.InjectExample(updated, mode, block); err != nil {
+			return fmt.Errorf("failed to inject %s example: %w", mode, err)
+		}
+	}
+
+	if writeErr := os.WriteFile(docsPath, []byte(updated), 0600); writeErr != nil {
+		return fmt.Errorf("failed to write file: %w", writeErr)
+	}
+	logger.Log.Info("examples injected", "path", docsPath, "count", len(markers))
+	return nil
+}
+
+// exampleCallName returns the name of the module block: --module-name or the module directory name.
+func exampleCallName(moduleRoot string) string {
+	if exampleModuleName != "" {
+		return exampleModuleName
+	}
+	return filepath.Base(moduleRoot)
+}
+
+// exampleCallSource returns the source of the module block: --source or the module path
+// relative to the working directory.
+func exampleCallSource(cwd, moduleRoot string) string {
+	if exampleSource != "" {
+		return exampleSource
+	}
+	rel := filepath.ToSlash(relativeTo(cwd, moduleRoot))
+	switch {
+	case rel == ".":
+		return "./"
+	case filepath.IsAbs(rel), strings.HasPrefix(rel, "../"):
+		return rel
+	default:
+		return "./" + rel
+	}
+}
@@ -1,18 +1,27 @@
 package marinatemd
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/glueckkanja/marinatemd/internal/config"
 	"github.com/glueckkanja/marinatemd/internal/hclparse"
 	"github.com/glueckkanja/marinatemd/internal/logger"
 	"github.com/glueckkanja/marinatemd/internal/paths"
 	"github.com/glueckkanja/marinatemd/internal/schema"
 	"github.com/glueckkanja/marinatemd/internal/yamlio"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
+var (
+	exportRecursive     bool
+	exportAcceptRenames bool
+)
+
 // exportCmd represents the export command that parses HCL and generates/merges YAML schemas.
 var exportCmd = &cobra.Command{
 	Use:   "export [module-path]",
@@ -21,35 +30,71 @@ var exportCmd = &cobra.Command{
 generate or update YAML schema files in the docs/variables/ directory.
 
 This command:
-  1. Parses variables.tf files for MARINATED markers
+  1. Parses the module's Terraform files (terraform_files patterns, default *.tf and *.tofu)
+     for MARINATED markers
   2. Generates structured YAML schemas for complex variable types
   3. Merges with existing YAML files to preserve user descriptions
   4. Creates new YAML files for newly discovered variables
+  5. Warns about examples and defaults that do not match their attribute's type
+     (see the validate command)
+
+When an attribute looks renamed (same type, default and children as a removed
+sibling with a similar name), export asks whether to move its documentation when
+run in a terminal. --accept-renames moves it without asking; otherwise the rename
+is only reported.
+
+With --recursive, every directory below module-path that contains MARINATED
+variables is exported as its own module, using the nearest .marinated.yml.
 
 Example:
   marinatemd export .
   marinatemd export /path/to/terraform/module
-  marinatemd export --config .marinated.yml .`,
+  marinatemd export --config .marinated.yml .
+  marinatemd export --recursive .`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runExport,
 }
 
 func init() {
 	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().BoolVarP(
+		&exportRecursive,
+		"recursive",
+		"r",
+		false,
+		"export every module below module-path that contains MARINATED variables",
+	)
+
+	exportCmd.Flags().BoolVar(
+		&exportAcceptRenames,
+		"accept-renames",
+		false,
+		"move documentation of renamed attributes without asking",
+	)
 }
 
-func runExport(_ *cobra.Command, args []string) error {
+func runExport(cmd *cobra.Command, args []string) error {
+	if exportRecursive {
+		return runRecursive(cmd, args, "export", exportModule)
+	}
+
 	moduleRoot, cfg, err := paths.SetupEnvironment(args)
 	if err != nil {
 		return err
 	}
 
+	return exportModule(moduleRoot, cfg)
+}
+
+// exportModule exports the MARINATED variables of a single module.
+func exportModule(moduleRoot string, cfg *config.Config) error {
 	// Resolve paths using config
 	exportPath := paths.ResolveExportPath(moduleRoot, cfg)
 
 	logger.Log.Info("exporting variables", "moduleRoot", moduleRoot, "exportPath", exportPath)
 
-	marinatedVars, err := parseAndExtractVariables(moduleRoot)
+	marinatedVars, err := parseAndExtractVariables(moduleRoot, cfg.TerraformFiles)
 	if err != nil {
 		return err
 	}
@@ -59,17 +104,52 @@ func runExport(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create variables directory: %w", mkdirErr)
 	}
 
-	if processErr := processMarinatedVariables(marinatedVars, exportPath, variablesDir); processErr != nil {
+	builder := newSchemaBuilder(cfg, renameConfirmer())
+	reports, processErr := processMarinatedVariables(marinatedVars, builder, exportPath, variablesDir)
+	if processErr != nil {
 		return processErr
 	}
 
-	printExportSummary(len(marinatedVars), variablesDir)
+	printExportSummary(reports, variablesDir, cfg)
 	return nil
 }
 
-func parseAndExtractVariables(variablesPath string) ([]*hclparse.Variable, error) {
+// newSchemaBuilder creates the schema builder used by export and check.
+// confirmRename decides on detected renames; nil only reports them.
+func newSchemaBuilder(cfg *config.Config, confirmRename func(from, to string) bool) *schema.Builder {
+	opts := &schema.BuilderOptions{ConfirmRename: confirmRename}
+	if cfg.Export != nil {
+		opts.KeepOrphans = cfg.Export.KeepOrphans
+	}
+	return schema.NewBuilderWithOptions(opts)
+}
+
+// renameConfirmer returns how export decides on detected renames: always accept with
+// --accept-renames, ask when stdin is a terminal, and only report them otherwise.
+func renameConfirmer() func(from, to string) bool {
+	if exportAcceptRenames {
+		return func(_, _ string) bool { return true }
+	}
+
+	if !isatty.IsTerminal(os.Stdin.Fd()) {
+		return nil
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	return func(from, to string) bool {
+		fmt.Fprintf(os.Stderr, "%s looks like it was renamed to %s. Move its documentation? [y/N] ", from, to)
+		answer, readErr := in.ReadString('\n')
+		if readErr != nil && answer == "" {
+			return false
+		}
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		return answer == "y" || answer == "yes"
+	}
+}
+
+func parseAndExtractVariables(variablesPath string, patterns *hclparse.FilePatterns) ([]*hclparse.Variable, error) {
 	logger.Log.Debug("parsing terraform variables", "path", variablesPath)
-	parser := hclparse.NewParser()
+	parser := hclparse.NewParserWithFilePatterns(patterns)
 	if err := parser.ParseVariables(variablesPath); err != nil {
 		return nil, fmt.Errorf("failed to parse variables: %w", err)
 	}
@@ -89,18 +169,24 @@ func parseAndExtractVariables(variablesPath string) ([]*hclparse.Variable, error
 	return marinatedVars, nil
 }
 
-func processMarinatedVariables(marinatedVars []*hclparse.Variable, docsPath, variablesDir string) error {
-	builder := schema.NewBuilder()
+func processMarinatedVariables(
+	marinatedVars []*hclparse.Variable,
+	builder *schema.Builder,
+	docsPath, variablesDir string,
+) ([]*schema.MergeReport, error) {
 	reader := yamlio.NewReader(docsPath)
 	writer := yamlio.NewWriter(docsPath)
 
 	logger.Log.Debug("processing variables", "count", len(marinatedVars))
+	reports := make([]*schema.MergeReport, 0, len(marinatedVars))
 	for _, variable := range marinatedVars {
-		if err := processVariable(variable, builder, reader, writer, variablesDir); err != nil {
-			return err
+		report, err := processVariable(variable, builder, reader, writer, variablesDir)
+		if err != nil {
+			return nil, err
 		}
+		reports = append(reports, report)
 	}
-	return nil
+	return reports, nil
 }
 
 func processVariable(
@@ -109,42 +195,99 @@ func processVariable(
 	reader *yamlio.Reader,
 	writer *yamlio.Writer,
 	variablesDir string,
-) error {
+) (*schema.MergeReport, error) {
 	logger.Log.Debug("processing variable", "name", variable.Name, "id", variable.MarinatedID)
 
-	newSchema, err := builder.BuildFromVariable(variable)
+	finalSchema, report, err := buildMergedSchema(variable, builder, reader)
 	if err != nil {
-		return fmt.Errorf("failed to build schema for variable %s: %w", variable.Name, err)
+		return nil, err
 	}
 
-	existingSchema, err := reader.ReadSchema(variable.MarinatedID)
+	yamlPath := filepath.Join(variablesDir, variable.MarinatedID+".yaml")
+	if writeErr := writer.WriteSchema(finalSchema); writeErr != nil {
+		return nil, fmt.Errorf("failed to write schema for %s: %w", variable.MarinatedID, writeErr)
+	}
+
+	logger.Log.Info("exported variable", "name", variable.Name, "path", yamlPath)
+	warnInvalidValues(finalSchema)
+	return report, nil
+}
+
+// buildMergedSchema builds the schema for a variable and merges it with the existing YAML file, if any.
+// This is the schema export writes to disk.
+func buildMergedSchema(
+	variable *hclparse.Variable,
+	builder *schema.Builder,
+	reader *yamlio.Reader,
+) (*schema.Schema, *schema.MergeReport, error) {
+	newSchema, err := builder.BuildFromVariable(variable)
 	if err != nil {
-		return fmt.Errorf("failed to read existing schema for %s: %w", variable.MarinatedID, err)
+		return nil, nil, fmt.Errorf("failed to build schema for variable %s: %w", variable.Name, err)
 	}
 
-	finalSchema, err := mergeOrUseNewSchema(newSchema, existingSchema, builder)
+	existingSchema, err := reader.ReadSchema(variable.MarinatedID)
 	if err != nil {
-		return fmt.Errorf("failed to merge schemas for %s: %w", variable.MarinatedID, err)
+		return nil, nil, fmt.Errorf("failed to read existing schema for %s: %w", variable.MarinatedID, err)
 	}
 
-	yamlPath := filepath.Join(variablesDir, variable.MarinatedID+".yaml")
-	if writeErr := writer.WriteSchema(finalSchema); writeErr != nil {
-		return fmt.Errorf("failed to write schema for %s: %w", variable.MarinatedID, writeErr)
+	finalSchema, report, err := mergeOrUseNewSchema(newSchema, existingSchema, builder)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to merge schemas for %s: %w", variable.MarinatedID, err)
 	}
 
-	logger.Log.Info("exported variable", "name", variable.Name, "path", yamlPath)
-	return nil
+	return finalSchema, report, nil
 }
 
-func mergeOrUseNewSchema(newSchema, existingSchema *schema.Schema, builder *schema.Builder) (*schema.Schema, error) {
+func mergeOrUseNewSchema(
+	newSchema, existingSchema *schema.Schema,
+	builder *schema.Builder,
+) (*schema.Schema, *schema.MergeReport, error) {
 	if existingSchema != nil {
 		logger.Log.Debug("merging with existing schema", "variable", newSchema.Variable)
-		return builder.MergeWithExisting(newSchema, existingSchema)
+		return builder.Merge(newSchema, existingSchema)
 	}
 	logger.Log.Debug("creating new schema", "variable", newSchema.Variable)
-	return newSchema, nil
+	return newSchema, &schema.MergeReport{Variable: newSchema.Variable}, nil
 }
 
-func printExportSummary(count int, variablesDir string) {
-	logger.Log.Info("export complete", "count", count, "directory", variablesDir)
+func printExportSummary(reports []*schema.MergeReport, variablesDir string, cfg *config.Config) {
+	keepOrphans := cfg.Export != nil && cfg.Export.KeepOrphans
+
+	for _, report := range reports {
+		for _, rename := range report.Renames {
+			if rename.Applied {
+				logger.Log.Warn("renamed attribute, documentation moved",
+					"variable", report.Variable, "from", rename.From, "to", rename.To)
+			} else {
+				logger.Log.Warn("possible rename detected, documentation not moved",
+					"variable", report.Variable, "from", rename.From, "to", rename.To,
+					"help", "Re-run export with --accept-renames to move it")
+			}
+		}
+		for _, path := range report.Reattached {
+			logger.Log.Info("re-attached orphaned description", "variable", report.Variable, "path", path)
+		}
+		if len(report.NeedsReview) > 0 {
+			logger.Log.Warn("type, required flag or default changed, descriptions marked needs_review",
+				"variable", report.Variable,
+				"paths", strings.Join(report.NeedsReview, ", "),
+				"help", "Check the descriptions, then remove needs_review from the YAML schema")
+		}
+		if len(report.Removed) == 0 {
+			continue
+		}
+
+		if keepOrphans {
+			logger.Log.Warn("removed attributes had descriptions, kept in orphans section",
+				"variable", report.Variable,
+				"paths", strings.Join(report.Removed, ", "))
+		} else {
+			logger.Log.Warn("removed attributes had descriptions, descriptions dropped",
+				"variable", report.Variable,
+				"paths", strings.Join(report.Removed, ", "),
+				"help", "Set export.keep_orphans: true to keep them in the YAML schema")
+		}
+	}
+
+	logger.Log.Info("export complete", "count", len(reports), "directory", variablesDir)
 }
@@ -1,6 +1,7 @@
 package marinatemd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -24,6 +25,7 @@ var (
 	markdownFile    string
 	injectType      string
 	terraformModule string
+	injectRecursive bool
 )
 
 // injectCmd represents the inject command that reads YAML schemas and injects markdown into documentation.
@@ -45,9 +47,15 @@ Flags:
                        Can be absolute or relative to current working directory.
                        Defaults to docs_file from configuration (relative to module root).
                        Required when inject-type is "markdown" or "both".
-  --terraform-module   Path to the Terraform module directory containing variables*.tf files.
+  --terraform-module   Path to the Terraform module directory. Files are selected by the
+                       terraform_files patterns in the configuration (default *.tf and *.tofu).
                        Can be absolute or relative to current working directory.
                        Required when inject-type is "terraform" or "both".
+  --recursive          Treat [schema-path] as a root directory and inject every module below it
+                       that contains MARINATED variables. Each module uses its nearest .marinated.yml;
+                       schemas are read from its export_path, markdown is injected into its docs_file
+                       and Terraform files are read from the module directory itself.
+                       Cannot be combined with --markdown-file or --terraform-module.
 
 Examples:
   # 1. Use default paths (./docs/variables/*.yaml → ./README.md)
@@ -61,7 +69,10 @@ Examples:
 
   # 4. Custom schema path and custom markdown file
   marinatemd inject /path/to/variables --markdown-file docs/API.md
-  marinatemd inject ./docs/variables --markdown-file /abs/path/to/doc.md`,
+  marinatemd inject ./docs/variables --markdown-file /abs/path/to/doc.md
+
+  # 5. Inject every module in a repository
+  marinatemd inject --recursive --inject-type both .`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runInject,
 }
@@ -89,9 +100,24 @@ func init() {
 		"",
 		"path to Terraform module directory (required for terraform or both inject types)",
 	)
+
+	injectCmd.Flags().BoolVarP(
+		&injectRecursive,
+		"recursive",
+		"r",
+		false,
+		"inject every module below schema-path that contains MARINATED variables",
+	)
 }
 
-func runInject(_ *cobra.Command, args []string) error {
+func runInject(cmd *cobra.Command, args []string) error {
+	if injectRecursive {
+		if validateErr := validateRecursiveInjectFlags(); validateErr != nil {
+			return validateErr
+		}
+		return runRecursive(cmd, args, "inject", injectModule)
+	}
+
 	// Load configuration (for template settings)
 	moduleRoot, cfg, err := paths.SetupEnvironment(args)
 	if err != nil {
@@ -118,14 +144,14 @@ func runInject(_ *cobra.Command, args []string) error {
 
 	// Handle markdown injection
 	if injectType == injectTypeMarkdown || injectType == injectTypeBoth {
-		if mdErr := injectMarkdown(schemaBasePath, markdownPath, cfg); mdErr != nil {
+		if _, mdErr := injectMarkdown(schemaBasePath, markdownPath, cfg); mdErr != nil {
 			return mdErr
 		}
 	}
 
 	// Handle Terraform injection
 	if injectType == injectTypeTerraform || injectType == injectTypeBoth {
-		if tfErr := injectTerraform(schemaBasePath, terraformPath, cfg); tfErr != nil {
+		if _, tfErr := injectTerraform(schemaBasePath, terraformPath, cfg); tfErr != nil {
 			return tfErr
 		}
 	}
@@ -133,19 +159,68 @@ func runInject(_ *cobra.Command, args []string) error {
 	return nil
 }
 
+// validateRecursiveInjectFlags validates flags for recursive injection.
+// Paths are resolved per module, so flags pointing at a single file or module are rejected.
+func validateRecursiveInjectFlags() error {
+	if markdownFile != "" {
+		return errors.New("--markdown-file cannot be used with --recursive (each module uses its docs_file)")
+	}
+	if terraformModule != "" {
+		return errors.New("--terraform-module cannot be used with --recursive (each module injects its own files)")
+	}
+	return validateInjectTypeValue(injectType)
+}
+
+// injectModule injects documentation for a single module found by a recursive scan.
+// Unlike a single-module run, variables that could not be injected count as a failure.
+func injectModule(moduleRoot string, cfg *config.Config) error {
+	schemaBasePath := paths.ResolveExportPath(moduleRoot, cfg)
+
+	if injectType == injectTypeMarkdown || injectType == injectTypeBoth {
+		failed, err := injectMarkdown(schemaBasePath, resolveDefaultDocsFile(moduleRoot, cfg), cfg)
+		if err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d variables were not injected into markdown", failed)
+		}
+	}
+
+	if injectType == injectTypeTerraform || injectType == injectTypeBoth {
+		failed, err := injectTerraform(schemaBasePath, moduleRoot, cfg)
+		if err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d variables were not injected into Terraform files", failed)
+		}
+	}
+
+	return nil
+}
+
 // validateInjectType validates the inject-type flag value.
 func validateInjectType() error {
+	if err := validateInjectTypeValue(injectType); err != nil {
+		return err
+	}
+
+	return validateTerraformModuleFlag()
+}
+
+// validateInjectTypeValue checks that an inject-type flag value names a known target.
+func validateInjectTypeValue(value string) error {
 	validTypes := map[string]bool{
 		injectTypeMarkdown:  true,
 		injectTypeTerraform: true,
 		injectTypeBoth:      true,
 	}
 
-	if !validTypes[injectType] {
-		return fmt.Errorf("invalid inject-type: %s (must be markdown, terraform, or both)", injectType)
+	if !validTypes[value] {
+		return fmt.Errorf("invalid inject-type: %s (must be markdown, terraform, or both)", value)
 	}
 
-	return validateTerraformModuleFlag()
+	return nil
 }
 
 func validateTerraformModuleFlag() error {
@@ -161,22 +236,23 @@ func requiresTerraformModule() bool {
 }
 
 // injectMarkdown handles markdown injection logic.
-func injectMarkdown(schemaBasePath, markdownPath string, cfg *config.Config) error {
+// Returns the number of markers that could not be injected.
+func injectMarkdown(schemaBasePath, markdownPath string, cfg *config.Config) (int, error) {
 	logger.Log.Info("injecting into markdown", "path", markdownPath)
 
 	// Verify markdown file exists
 	if _, statErr := os.Stat(markdownPath); statErr != nil {
-		return fmt.Errorf("markdown file not found: %s", markdownPath)
+		return 0, fmt.Errorf("markdown file not found: %s", markdownPath)
 	}
 	logger.Log.Debug("markdown file found", "path", markdownPath)
 
 	injector := markdown.NewInjector()
 	markers, err := findAndValidateMarkers(injector, markdownPath)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	if len(markers) == 0 {
-		return nil
+		return 0, nil
 	}
 
 	// Create renderer with template config from configuration
@@ -184,40 +260,41 @@ func injectMarkdown(schemaBasePath, markdownPath string, cfg *config.Config) err
 	reader := yamlio.NewReader(schemaBasePath)
 	successCount := processInjectMarkers(markers, markdownPath, renderer, injector, reader)
 	printInjectSummary("markdown", successCount, len(markers))
-	return nil
+	return len(markers) - successCount, nil
 }
 
 // injectTerraform handles Terraform injection logic.
-func injectTerraform(schemaBasePath, terraformPath string, cfg *config.Config) error {
+// Returns the number of markers that could not be injected.
+func injectTerraform(schemaBasePath, terraformPath string, cfg *config.Config) (int, error) {
 	logger.Log.Info("injecting into Terraform", "path", terraformPath)
 
 	// Verify terraform module directory exists
 	if _, statErr := os.Stat(terraformPath); statErr != nil {
-		return fmt.Errorf("terraform module directory not found: %s", terraformPath)
+		return 0, fmt.Errorf("terraform module directory not found: %s", terraformPath)
 	}
 	logger.Log.Debug("terraform module found", "path", terraformPath)
 
-	tfInjector := hclparse.NewTerraformInjector(terraformPath)
+	tfInjector := hclparse.NewTerraformInjectorWithFilePatterns(terraformPath, cfg.TerraformFiles)
 	markers, err := tfInjector.FindMarkers()
 	if err != nil {
-		return fmt.Errorf("failed to find markers in Terraform files: %w", err)
+		return 0, fmt.Errorf("failed to find markers in Terraform files: %w", err)
 	}
 
 	if len(markers) == 0 {
 		logger.Log.Warn("no MARINATED markers found in Terraform variables",
 			"path", terraformPath,
 			"help", "Add <!-- MARINATED: variable_name --> to variable descriptions")
-		return nil
+		return 0, nil
 	}
 
 	logger.Log.Info("found markers in Terraform", "count", len(markers))
 
 	// Create renderer with template config from configuration
-	renderer := markdown.NewRendererWithTemplate(cfg.MarkdownTemplate)
+	renderer := markdown.NewTerraformRenderer(cfg.MarkdownTemplate)
 	reader := yamlio.NewReader(schemaBasePath)
 	successCount := processTerraformMarkers(markers, tfInjector, renderer, reader)
 	printInjectSummary("Terraform", successCount, len(markers))
-	return nil
+	return len(markers) - successCount, nil
 }
 
 // processTerraformMarkers processes each marker for Terraform injection.
@@ -0,0 +1,169 @@
+package marinatemd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/glueckkanja/marinatemd/internal/config"
+	"github.com/glueckkanja/marinatemd/internal/jsonschema"
+	"github.com/glueckkanja/marinatemd/internal/logger"
+	"github.com/glueckkanja/marinatemd/internal/paths"
+	"github.com/glueckkanja/marinatemd/internal/yamlio"
+	"github.com/spf13/cobra"
+)
+
+var (
+	jsonSchemaOutput      string
+	jsonSchemaPerVariable bool
+	jsonSchemaRecursive   bool
+)
+
+// jsonSchemaCmd represents the jsonschema command that exports JSON Schema for variable values.
+var jsonSchemaCmd = &cobra.Command{
+	Use:   "jsonschema [module-path]",
+	Short: "Export JSON Schema (draft 2020-12) for MARINATED variables",
+	Long: `Export a JSON Schema (draft 2020-12) describing the values of MARINATED variables,
+so editors can offer completion and validation for *.tfvars.json files.
+
+The schemas are built from the Terraform types merged with the YAML schemas, like
+export does, without writing the YAML files. They contain:
+  - Types, element and value types of lists, sets, maps and tuples
+  - Required attributes and required variables (variables without a default)
+  - null for optional attributes and nullable variables
+  - Descriptions, defaults and examples (left out for sensitive variables)
+  - Allowed values, patterns and number ranges from validation rules
+
+By default one schema for all module inputs is written to inputs.schema.json in
+the export directory. With --per-variable, one schema per variable is written to
+the jsonschema directory below the export directory instead.
+
+Flags:
+  --output        Output file, or directory with --per-variable (relative to the module)
+  --per-variable  Write one schema per variable instead of one for all inputs
+  --recursive     Export every module below module-path that contains MARINATED variables
+
+Example:
+  marinatemd jsonschema .
+  marinatemd jsonschema --output inputs.schema.json .
+  marinatemd jsonschema --per-variable .`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runJSONSchema,
+}
+
+func init() {
+	rootCmd.AddCommand(jsonSchemaCmd)
+
+	jsonSchemaCmd.Flags().StringVarP(
+		&jsonSchemaOutput,
+		"output",
+		"o",
+		"",
+		"output file, or directory with --per-variable (defaults to the export directory)",
+	)
+
+	jsonSchemaCmd.Flags().BoolVar(
+		&jsonSchemaPerVariable,
+		"per-variable",
+		false,
+		"write one schema per variable instead of one for all inputs",
+	)
+
+	jsonSchemaCmd.Flags().BoolVarP(
+		&jsonSchemaRecursive,
+		"recursive",
+		"r",
+		false,
+		"export every module below module-path that contains MARINATED variables",
+	)
+}
+
+func runJSONSchema(cmd *cobra.Command, args []string) error {
+	if jsonSchemaRecursive {
+		return runRecursive(cmd, args, "jsonschema", jsonSchemaModule)
+	}
+
+	moduleRoot, cfg, err := paths.SetupEnvironment(args)
+	if err != nil {
+		return err
+	}
+
+	return jsonSchemaModule(moduleRoot, cfg)
+}
+
+// jsonSchemaModule writes the JSON Schema of a single module's MARINATED variables.
+func jsonSchemaModule(moduleRoot string, cfg *config.Config) error {
+	exportPath := paths.ResolveExportPath(moduleRoot, cfg)
+
+	logger.Log.Info("exporting JSON schema", "moduleRoot", moduleRoot, "exportPath", exportPath)
+
+	marinatedVars, err := parseAndExtractVariables(moduleRoot, cfg.TerraformFiles)
+	if err != nil {
+		return err
+	}
+	if len(marinatedVars) == 0 {
+		return nil
+	}
+
+	builder := newSchemaBuilder(cfg, nil)
+	reader := yamlio.NewReader(exportPath)
+
+	if jsonSchemaPerVariable {
+		outputDir := resolveJSONSchemaOutput(moduleRoot, filepath.Join(exportPath, "jsonschema"))
+		for _, variable := range marinatedVars {
+			s, _, buildErr := buildMergedSchema(variable, builder, reader)
+			if buildErr != nil {
+				return buildErr
+			}
+			path := filepath.Join(outputDir, variable.MarinatedID+".schema.json")
+			if writeErr := writeJSONSchema(path, jsonschema.FromSchema(s)); writeErr != nil {
+				return writeErr
+			}
+		}
+		logger.Log.Info("JSON schemas written", "count", len(marinatedVars), "path", outputDir)
+		return nil
+	}
+
+	inputs := make([]jsonschema.Input, 0, len(marinatedVars))
+	for _, variable := range marinatedVars {
+		s, _, buildErr := buildMergedSchema(variable, builder, reader)
+		if buildErr != nil {
+			return buildErr
+		}
+		inputs = append(inputs, jsonschema.Input{Name: variable.Name, Required: !variable.HasDefault, Schema: s})
+	}
+
+	path := resolveJSONSchemaOutput(moduleRoot, filepath.Join(exportPath, "inputs.schema.json"))
+	if writeErr := writeJSONSchema(path, jsonschema.ForInputs(filepath.Base(moduleRoot), inputs)); writeErr != nil {
+		return writeErr
+	}
+	logger.Log.Info("JSON schema written", "count", len(inputs), "path", path)
+	return nil
+}
+
+// resolveJSONSchemaOutput returns the --output path, relative to the module root, or fallback if it is not set.
+func resolveJSONSchemaOutput(moduleRoot, fallback string) string {
+	switch {
+	case jsonSchemaOutput == "":
+		return fallback
+	case filepath.IsAbs(jsonSchemaOutput):
+		return jsonSchemaOutput
+	default:
+		return filepath.Join(moduleRoot, jsonSchemaOutput)
+	}
+}
+
+func writeJSONSchema(path string, doc *jsonschema.Schema) error {
+	data, err := jsonschema.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if mkErr := os.MkdirAll(filepath.Dir(path), 0750); mkErr != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, mkErr)
+	}
+	if writeErr := os.WriteFile(path, data, 0600); writeErr != nil {
+		return fmt.Errorf("failed to write JSON schema %s: %w", path, writeErr)
+	}
+	logger.Log.Debug("wrote JSON schema", "path", path, "title", doc.Title)
+	return nil
+}
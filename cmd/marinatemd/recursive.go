@@ -0,0 +1,93 @@
+package marinatemd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/glueckkanja/marinatemd/internal/config"
+	"github.com/glueckkanja/marinatemd/internal/logger"
+	"github.com/glueckkanja/marinatemd/internal/paths"
+	"github.com/spf13/cobra"
+)
+
+// moduleRunner runs a command against a single module with that module's configuration.
+type moduleRunner func(moduleRoot string, cfg *config.Config) error
+
+// moduleResult records the outcome of running a command against one module.
+type moduleResult struct {
+	root string
+	err  error
+}
+
+// runRecursive discovers every module below the given path and runs the command against each one.
+// All modules are processed even if some fail; an aggregated summary is printed at the end and
+// an error is returned if any module failed.
+func runRecursive(cmd *cobra.Command, args []string, name string, run moduleRunner) error {
+	root, fallback, err := paths.SetupEnvironment(args)
+	if err != nil {
+		return err
+	}
+
+	modules, err := paths.DiscoverModules(root, fallback)
+	if err != nil {
+		return err
+	}
+
+	if len(modules) == 0 {
+		logger.Log.Warn("no modules with MARINATED variables found",
+			"path", root,
+			"help", "Add <!-- MARINATED: variable_name --> to variable descriptions to enable documentation")
+		return nil
+	}
+
+	logger.Log.Info("discovered modules", "count", len(modules), "root", root)
+
+	results := make([]moduleResult, 0, len(modules))
+	for _, module := range modules {
+		moduleErr := module.Err
+		if moduleErr == nil {
+			logger.Log.Info("processing module", "command", name, "module", module.Root, "config", module.ConfigFile)
+			moduleErr = run(module.Root, module.Config)
+		}
+		if moduleErr != nil {
+			logger.Log.Debug("module failed", "command", name, "module", module.Root, "error", moduleErr)
+		}
+		results = append(results, moduleResult{root: module.Root, err: moduleErr})
+	}
+
+	failed := printRecursiveSummary(cmd.OutOrStdout(), name, root, results)
+	if failed > 0 {
+		// The summary already explains what went wrong; usage text would only add noise.
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%s failed for %d of %d modules", name, failed, len(results))
+	}
+
+	return nil
+}
+
+// printRecursiveSummary prints one line per module and returns the number of failed modules.
+func printRecursiveSummary(out io.Writer, name, root string, results []moduleResult) int {
+	failed := 0
+	for _, result := range results {
+		if result.err != nil {
+			failed++
+		}
+	}
+
+	fmt.Fprintf(out, "%s: %d modules, %d succeeded, %d failed\n", name, len(results), len(results)-failed, failed)
+	for _, result := range results {
+		relPath, relErr := filepath.Rel(root, result.root)
+		if relErr != nil {
+			relPath = result.root
+		}
+
+		if result.err != nil {
+			fmt.Fprintf(out, "  FAIL %s: %v\n", relPath, result.err)
+		} else {
+			fmt.Fprintf(out, "  ok   %s\n", relPath)
+		}
+	}
+
+	return failed
+}
@@ -24,8 +24,10 @@ generates or updates YAML schema files, and injects hierarchical markdown docume
 into README.md or other documentation files.
 
 Commands:
-  export  - Parse HCL variables and generate/merge YAML schema files
-  inject  - Read YAML schemas and inject markdown into documentation
+  export   - Parse HCL variables and generate/merge YAML schema files
+  inject   - Read YAML schemas and inject markdown into documentation
+  check    - Verify that YAML schemas and injected documentation are up to date
+  coverage - Report attributes that still have TODO placeholder descriptions
 
 Example:
   marinatemd export .
@@ -1,12 +1,9 @@
 package marinatemd
 
 import (
-	"errors"
 	"fmt"
-	"io/fs"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/glueckkanja/marinatemd/internal/config"
 	"github.com/glueckkanja/marinatemd/internal/logger"
@@ -21,6 +18,7 @@ var (
 	splitOutputDir  string
 	splitHeaderFile string
 	splitFooterFile string
+	splitRecursive  bool
 )
 
 // splitCmd represents the split command that post-processes markdown files.
@@ -38,10 +36,15 @@ This command:
 This is useful when you want individual documentation files for each variable
 instead of a single monolithic README.
 
+With --recursive, every directory below module-path that contains MARINATED
+variables is split as its own module, using the nearest .marinated.yml.
+Relative --input, --output, --header and --footer paths are resolved per module.
+
 Example:
   marinatemd split .
   marinatemd split --input docs/README.md --output docs/variables .
-  marinatemd split --header _header.md --footer _footer.md .`,
+  marinatemd split --header _header.md --footer _footer.md .
+  marinatemd split --recursive .`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runSplit,
 }
@@ -76,14 +79,31 @@ func init() {
 		"",
 		"path to footer file to append to each split file",
 	)
+
+	splitCmd.Flags().BoolVarP(
+		&splitRecursive,
+		"recursive",
+		"r",
+		false,
+		"split every module below module-path that contains MARINATED variables",
+	)
 }
 
-func runSplit(_ *cobra.Command, args []string) error {
+func runSplit(cmd *cobra.Command, args []string) error {
+	if splitRecursive {
+		return runRecursive(cmd, args, "split", splitModule)
+	}
+
 	moduleRoot, cfg, err := paths.SetupEnvironment(args)
 	if err != nil {
 		return err
 	}
 
+	return splitModule(moduleRoot, cfg)
+}
+
+// splitModule splits the documentation file of a single module.
+func splitModule(moduleRoot string, cfg *config.Config) error {
 	inputPath := resolveInputPath(moduleRoot, cfg)
 	outputDir := resolveOutputDir(moduleRoot, cfg)
 	headerPath, footerPath := resolveTemplatePaths(moduleRoot, cfg)
@@ -222,17 +242,12 @@ func applyConfigNameOverrides(splitter *markdown.Splitter, moduleRoot string, cf
 	exportPath := paths.ResolveExportPath(moduleRoot, cfg)
 	reader := yamlio.NewReader(exportPath)
 
-	files, err := os.ReadDir(filepath.Join(exportPath, "variables"))
-	if err != nil && !errors.Is(err, fs.ErrNotExist) {
-		return fmt.Errorf("failed to list schema files: %w", err)
+	variables, err := reader.ListSchemas()
+	if err != nil {
+		return err
 	}
 
-	for _, entry := range files {
-		if entry.IsDir() || filepath.Ext(entry.Name()) != ".yaml" {
-			continue
-		}
-
-		variable := strings.TrimSuffix(entry.Name(), ".yaml")
+	for _, variable := range variables {
 		schemaFile, readErr := reader.ReadSchema(variable)
 		if readErr != nil {
 			return fmt.Errorf("failed to read schema for %s: %w", variable, readErr)
@@ -0,0 +1,133 @@
+package marinatemd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/glueckkanja/marinatemd/internal/config"
+	"github.com/glueckkanja/marinatemd/internal/logger"
+	"github.com/glueckkanja/marinatemd/internal/paths"
+	"github.com/glueckkanja/marinatemd/internal/schema"
+	"github.com/glueckkanja/marinatemd/internal/yamlio"
+	"github.com/spf13/cobra"
+)
+
+var validateRecursive bool
+
+// errInvalidValues is returned when examples or defaults do not match their attribute's type.
+var errInvalidValues = errors.New("examples or defaults do not match their types")
+
+// validateCmd represents the validate command that type-checks examples and defaults.
+var validateCmd = &cobra.Command{
+	Use:   "validate [module-path]",
+	Short: "Type-check examples and defaults in the YAML schemas",
+	Long: `Check every example and default in the YAML schemas against the type of its
+attribute, converting values the way Terraform converts variable values.
+
+A value is reported when it cannot be converted (example: "abc" on a number),
+when an object is missing a required attribute, or when an object has an
+attribute its type does not declare. Export runs the same checks and logs
+mismatches as warnings.
+
+Flags:
+  --recursive   Validate every module below module-path that contains MARINATED variables
+
+Example:
+  marinatemd validate .
+  marinatemd validate --recursive .`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runValidate,
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+
+	validateCmd.Flags().BoolVarP(
+		&validateRecursive,
+		"recursive",
+		"r",
+		false,
+		"validate every module below module-path that contains MARINATED variables",
+	)
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+	run := func(moduleRoot string, cfg *config.Config) error {
+		return validateModule(out, moduleRoot, cfg)
+	}
+
+	if validateRecursive {
+		return runRecursive(cmd, args, "validate", run)
+	}
+
+	moduleRoot, cfg, err := paths.SetupEnvironment(args)
+	if err != nil {
+		return err
+	}
+
+	if validateErr := run(moduleRoot, cfg); validateErr != nil {
+		if errors.Is(validateErr, errInvalidValues) {
+			// The report already explains what went wrong; usage text would only add noise.
+			cmd.SilenceUsage = true
+		}
+		return validateErr
+	}
+
+	return nil
+}
+
+// validateModule type-checks the schemas in the module's export directory and writes
+// one line per mismatch to out.
+func validateModule(out io.Writer, moduleRoot string, cfg *config.Config) error {
+	exportPath := paths.ResolveExportPath(moduleRoot, cfg)
+	reader := yamlio.NewReader(exportPath)
+
+	logger.Log.Info("validating schemas", "moduleRoot", moduleRoot, "exportPath", exportPath)
+
+	variables, err := reader.ListSchemas()
+	if err != nil {
+		return err
+	}
+	if len(variables) == 0 {
+		logger.Log.Warn("no YAML schemas found",
+			"path", exportPath,
+			"help", "Run 'marinatemd export' first to generate YAML schemas")
+	}
+
+	invalid := 0
+	for _, variable := range variables {
+		s, readErr := reader.ReadSchema(variable)
+		if readErr != nil {
+			return fmt.Errorf("failed to read schema for %s: %w", variable, readErr)
+		}
+		if s.Variable == "" {
+			s.Variable = variable
+		}
+
+		for _, valueErr := range schema.ValidateValues(s) {
+			fmt.Fprintln(out, valueErr.Error())
+			invalid++
+		}
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%w: %d found", errInvalidValues, invalid)
+	}
+
+	logger.Log.Info("all examples and defaults match their types", "count", len(variables))
+	return nil
+}
+
+// warnInvalidValues logs every example or default of a schema that does not match its type.
+func warnInvalidValues(s *schema.Schema) {
+	for _, valueErr := range schema.ValidateValues(s) {
+		logger.Log.Warn("value does not match the attribute type",
+			"variable", s.Variable,
+			"path", valueErr.Path,
+			"field", valueErr.Field,
+			"error", valueErr.Message,
+			"help", "Fix the value in the YAML schema, then run 'marinatemd validate'")
+	}
+}
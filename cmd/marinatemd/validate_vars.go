@@ -0,0 +1,225 @@
+package marinatemd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/glueckkanja/marinatemd/internal/config"
+	"github.com/glueckkanja/marinatemd/internal/hclparse"
+	"github.com/glueckkanja/marinatemd/internal/logger"
+	"github.com/glueckkanja/marinatemd/internal/paths"
+	"github.com/glueckkanja/marinatemd/internal/schema"
+	"github.com/glueckkanja/marinatemd/internal/yamlio"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateVarsFiles     []string
+	validateVarsRecursive bool
+)
+
+// errInvalidVars is returned when variable definitions files do not match the schemas.
+var errInvalidVars = errors.New("variable values do not match their schemas")
+
+// validateVarsCmd represents the validate-vars command that checks tfvars files against the schemas.
+var validateVarsCmd = &cobra.Command{
+	Use:   "validate-vars [module-path]",
+	Short: "Check tfvars files against the YAML schemas",
+	Long: `Check the values in variable definitions files (*.tfvars, *.auto.tfvars and
+*.tfvars.json) against the YAML schemas of the module's MARINATED variables.
+
+Terraform silently drops object attributes its type doesn't declare, so a typo in an
+optional attribute goes unnoticed. This command reports, with file:line:column:
+  - Attributes the type does not declare
+  - Missing required attributes
+  - Values that cannot be converted to the attribute's type
+  - Values for variables the module does not declare
+
+Variables without a YAML schema are not checked. Run 'marinatemd export' first.
+
+Flags:
+  --var-file    File to check, relative to the module (repeatable; defaults to all
+                *.tfvars and *.tfvars.json files in the module directory)
+  --recursive   Check every module below module-path that contains MARINATED variables
+
+Example:
+  marinatemd validate-vars .
+  marinatemd validate-vars --var-file environments/prod.tfvars .
+  marinatemd validate-vars --recursive .`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runValidateVars,
+}
+
+func init() {
+	rootCmd.AddCommand(validateVarsCmd)
+
+	validateVarsCmd.Flags().StringArrayVar(
+		&validateVarsFiles,
+		"var-file",
+		nil,
+		"variable definitions file to check, relative to the module (repeatable)",
+	)
+
+	validateVarsCmd.Flags().BoolVarP(
+		&validateVarsRecursive,
+		"recursive",
+		"r",
+		false,
+		"check every module below module-path that contains MARINATED variables",
+	)
+}
+
+func runValidateVars(cmd *cobra.Command, args []string) error {
+	// Diagnostics use paths relative to the working directory, like compilers do.
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	run := func(moduleRoot string, cfg *config.Config) error {
+		return validateVarsModule(out, cwd, moduleRoot, cfg)
+	}
+
+	if validateVarsRecursive {
+		return runRecursive(cmd, args, "validate-vars", run)
+	}
+
+	moduleRoot, cfg, err := paths.SetupEnvironment(args)
+	if err != nil {
+		return err
+	}
+
+	if validateErr := run(moduleRoot, cfg); validateErr != nil {
+		if errors.Is(validateErr, errInvalidVars) {
+			// The diagnostics already explain what went wrong; usage text would only add noise.
+			cmd.SilenceUsage = true
+		}
+		return validateErr
+	}
+
+	return nil
+}
+
+// validateVarsModule checks the variable definitions files of a single module and writes
+// one diagnostic per problem to out. File names are relative to base.
+func validateVarsModule(out io.Writer, base, moduleRoot string, cfg *config.Config) error {
+	exportPath := paths.ResolveExportPath(moduleRoot, cfg)
+
+	files, err := resolveVarsFiles(moduleRoot)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		logger.Log.Warn("no variable definitions files found", "moduleRoot", moduleRoot)
+		return nil
+	}
+
+	logger.Log.Info("validating variable definitions", "moduleRoot", moduleRoot, "files", len(files))
+
+	parser := hclparse.NewParserWithFilePatterns(cfg.TerraformFiles)
+	if parseErr := parser.ParseVariables(moduleRoot); parseErr != nil {
+		return fmt.Errorf("failed to parse variables: %w", parseErr)
+	}
+
+	schemas, err := readVariableSchemas(parser.Variables(), yamlio.NewReader(exportPath))
+	if err != nil {
+		return err
+	}
+
+	invalid := 0
+	for _, file := range files {
+		varsFile, parseErr := hclparse.ParseVarsFile(file)
+		if parseErr != nil {
+			return parseErr
+		}
+		name := relativeTo(base, file)
+
+		for _, value := range varsFile.Values {
+			s, declared := schemas[value.Name]
+			if !declared {
+				fmt.Fprintf(out, "%s:%d:%d: %s: value for undeclared variable\n",
+					name, value.Range.Start.Line, value.Range.Start.Column, value.Name)
+				invalid++
+				continue
+			}
+			if s == nil {
+				continue
+			}
+
+			// Report problems in source order rather than attribute order
+			problems := schema.CheckValue(s, value.Value)
+			ranges := make(map[*schema.ValueProblem]hcl.Range, len(problems))
+			for _, problem := range problems {
+				ranges[problem] = value.RangeOf(problem.Path)
+			}
+			sort.SliceStable(problems, func(i, j int) bool {
+				return ranges[problems[i]].Start.Byte < ranges[problems[j]].Start.Byte
+			})
+
+			for _, problem := range problems {
+				rng := ranges[problem]
+				fmt.Fprintf(out, "%s:%d:%d: %s: %s\n", name, rng.Start.Line, rng.Start.Column, value.Name, problem.Error())
+				invalid++
+			}
+		}
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%w: %d problems found", errInvalidVars, invalid)
+	}
+
+	logger.Log.Info("all variable definitions match their schemas", "files", len(files))
+	return nil
+}
+
+// resolveVarsFiles returns the --var-file paths, relative to the module root, or all
+// variable definitions files in the module directory if none are given.
+func resolveVarsFiles(moduleRoot string) ([]string, error) {
+	if len(validateVarsFiles) == 0 {
+		files, err := hclparse.VarsFilePatterns().Files(moduleRoot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find variable definitions files: %w", err)
+		}
+		return files, nil
+	}
+
+	files := make([]string, 0, len(validateVarsFiles))
+	for _, file := range validateVarsFiles {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(moduleRoot, file)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// readVariableSchemas returns the YAML schema of every declared variable by variable name.
+// Variables that are not MARINATED or have no YAML schema map to nil.
+func readVariableSchemas(variables []*hclparse.Variable, reader *yamlio.Reader) (map[string]*schema.Schema, error) {
+	schemas := make(map[string]*schema.Schema, len(variables))
+	for _, variable := range variables {
+		schemas[variable.Name] = nil
+		if !variable.Marinated {
+			continue
+		}
+
+		s, err := reader.ReadSchema(variable.MarinatedID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema for %s: %w", variable.MarinatedID, err)
+		}
+		if s == nil {
+			logger.Log.Warn("no schema found, variable is not checked",
+				"variable", variable.Name,
+				"help", "Run 'marinatemd export' first to generate YAML schemas")
+			continue
+		}
+		schemas[variable.Name] = s
+	}
+	return schemas, nil
+}
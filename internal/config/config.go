@@ -1,6 +1,11 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/glueckkanja/marinatemd/internal/hclparse"
 	"github.com/glueckkanja/marinatemd/internal/logger"
 	"github.com/glueckkanja/marinatemd/internal/markdown"
 	"github.com/spf13/viper"
@@ -23,10 +28,23 @@ type Config struct {
 	// MarkdownTemplate configures how markdown is generated from schema
 	MarkdownTemplate *markdown.TemplateConfig `mapstructure:"markdown_template"`
 
+	// TerraformFiles selects which files in the module are scanned for variables
+	TerraformFiles *hclparse.FilePatterns `mapstructure:"terraform_files"`
+
+	// Export configures the export command behavior
+	Export *ExportConfig `mapstructure:"export"`
+
 	// Split configures the split command behavior
 	Split *SplitConfig `mapstructure:"split"`
 }
 
+// ExportConfig represents configuration for the export command.
+type ExportConfig struct {
+	// KeepOrphans keeps the descriptions of attributes removed from the HCL type
+	// in an orphans section of the YAML schema, so they can be re-attached later
+	KeepOrphans bool `mapstructure:"keep_orphans"`
+}
+
 // SplitConfig represents configuration for the split command.
 type SplitConfig struct {
 	// InputPath is the input markdown file to split (relative to export_path).
@@ -43,9 +61,61 @@ type SplitConfig struct {
 	FooterFile string `mapstructure:"footer_file"`
 }
 
+// configFileNames lists the config file locations checked in a module directory, in priority order.
+var configFileNames = []string{
+	".marinated.yml",
+	filepath.Join(".config", ".marinated.yml"),
+}
+
 // Load returns the configuration loaded from viper.
 // This should be called after Viper has been initialized by Cobra.
 func Load() (*Config, error) {
+	return load(viper.GetViper())
+}
+
+// LoadFile returns the configuration read from the given config file.
+// Unlike Load, it uses its own viper instance, so it can be used to load
+// a separate configuration per module.
+func LoadFile(path string) (*Config, error) {
+	logger.Log.Debug("loading configuration file", "path", path)
+
+	v := viper.New()
+	setDefaults(v)
+	v.SetEnvPrefix("MARINATED")
+	v.AutomaticEnv()
+	v.SetConfigFile(path)
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	return load(v)
+}
+
+// FindNearest returns the config file closest to dir, searching dir and its parent
+// directories up to and including stopAt. Returns an empty string if none is found.
+func FindNearest(dir, stopAt string) string {
+	for {
+		for _, name := range configFileNames {
+			candidate := filepath.Join(dir, name)
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate
+			}
+		}
+
+		if dir == stopAt {
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// load builds the configuration from a viper instance.
+func load(v *viper.Viper) (*Config, error) {
 	logger.Log.Debug("loading configuration")
 
 	cfg := &Config{
@@ -54,6 +124,10 @@ func Load() (*Config, error) {
 		DocsFile:         "README.md",
 		Verbose:          false,
 		MarkdownTemplate: markdown.DefaultTemplateConfig(),
+		TerraformFiles:   hclparse.DefaultFilePatterns(),
+		Export: &ExportConfig{
+			KeepOrphans: false,
+		},
 		Split: &SplitConfig{
 			InputPath:  "", // Empty means use DocsFile
 			OutputDir:  "variables",
@@ -68,7 +142,7 @@ func Load() (*Config, error) {
 		"split.output_dir", cfg.Split.OutputDir)
 
 	// Unmarshal viper config into struct
-	if err := viper.Unmarshal(cfg); err != nil {
+	if err := v.Unmarshal(cfg); err != nil {
 		logger.Log.Debug("failed to unmarshal config", "error", err)
 		return nil, err
 	}
@@ -79,7 +153,10 @@ func Load() (*Config, error) {
 		"split.input_path", cfg.Split.InputPath,
 		"split.output_dir", cfg.Split.OutputDir,
 		"split.header_file", cfg.Split.HeaderFile,
-		"split.footer_file", cfg.Split.FooterFile)
+		"split.footer_file", cfg.Split.FooterFile,
+		"terraform_files.include", cfg.TerraformFiles.Include,
+		"terraform_files.exclude", cfg.TerraformFiles.Exclude,
+		"export.keep_orphans", cfg.Export.KeepOrphans)
 
 	// Validate markdown template configuration
 	if err := cfg.MarkdownTemplate.Validate(); err != nil {
@@ -87,34 +164,61 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	if err := cfg.TerraformFiles.Validate(); err != nil {
+		logger.Log.Debug("config validation failed", "error", err)
+		return nil, err
+	}
+
 	logger.Log.Debug("configuration validated successfully")
 	return cfg, nil
 }
 
-// SetDefaults configures default values for viper
 // SetDefaults sets default configuration values.
 // This should be called during initialization before config file is read.
 func SetDefaults() {
+	setDefaults(viper.GetViper())
+}
+
+// setDefaults configures default values on a viper instance.
+func setDefaults(v *viper.Viper) {
 	logger.Log.Debug("setting viper default values")
 
-	viper.SetDefault("export_path", "docs")
-	viper.SetDefault("docs_file", "README.md")
-	viper.SetDefault("verbose", false)
+	v.SetDefault("export_path", "docs")
+	v.SetDefault("docs_file", "README.md")
+	v.SetDefault("verbose", false)
 
 	// Set markdown template defaults
 	defaultTemplate := markdown.DefaultTemplateConfig()
-	viper.SetDefault("markdown_template.attribute_template", defaultTemplate.AttributeTemplate)
-	viper.SetDefault("markdown_template.required_text", defaultTemplate.RequiredText)
-	viper.SetDefault("markdown_template.optional_text", defaultTemplate.OptionalText)
-	viper.SetDefault("markdown_template.escape_mode", defaultTemplate.EscapeMode)
-	viper.SetDefault("markdown_template.indent_style", defaultTemplate.IndentStyle)
-	viper.SetDefault("markdown_template.indent_size", defaultTemplate.IndentSize)
+	v.SetDefault("markdown_template.attribute_template", defaultTemplate.AttributeTemplate)
+	v.SetDefault("markdown_template.required_text", defaultTemplate.RequiredText)
+	v.SetDefault("markdown_template.optional_text", defaultTemplate.OptionalText)
+	v.SetDefault("markdown_template.escape_mode", defaultTemplate.EscapeMode)
+	v.SetDefault("markdown_template.indent_style", defaultTemplate.IndentStyle)
+	v.SetDefault("markdown_template.indent_size", defaultTemplate.IndentSize)
+	v.SetDefault("markdown_template.review_badge", defaultTemplate.ReviewBadge)
+	v.SetDefault("markdown_template.sensitive_mask", defaultTemplate.SensitiveMask)
+	v.SetDefault("markdown_template.value_format", defaultTemplate.ValueFormat)
+	v.SetDefault("markdown_template.code_block_threshold", defaultTemplate.CodeBlockThreshold)
+	v.SetDefault("markdown_template.render_mode", defaultTemplate.RenderMode)
+	v.SetDefault("markdown_template.heading_level", defaultTemplate.HeadingLevel)
+	v.SetDefault("markdown_template.heading_leaves", defaultTemplate.HeadingLeaves)
+	v.SetDefault("markdown_template.collapse_depth", defaultTemplate.CollapseDepth)
+	v.SetDefault("markdown_template.collapse_threshold", defaultTemplate.CollapseThreshold)
+	v.SetDefault("markdown_template.collapse_summary", defaultTemplate.CollapseSummary)
+
+	// Set Terraform file pattern defaults
+	defaultPatterns := hclparse.DefaultFilePatterns()
+	v.SetDefault("terraform_files.include", defaultPatterns.Include)
+	v.SetDefault("terraform_files.exclude", defaultPatterns.Exclude)
+
+	// Set export command defaults
+	v.SetDefault("export.keep_orphans", false)
 
 	// Set split command defaults
-	viper.SetDefault("split.input_path", "") // Empty means use docs_file
-	viper.SetDefault("split.output_dir", "variables")
-	viper.SetDefault("split.header_file", "")
-	viper.SetDefault("split.footer_file", "")
+	v.SetDefault("split.input_path", "") // Empty means use docs_file
+	v.SetDefault("split.output_dir", "variables")
+	v.SetDefault("split.header_file", "")
+	v.SetDefault("split.footer_file", "")
 
 	logger.Log.Debug("viper defaults configured")
 }
@@ -43,6 +43,23 @@ func TestLoad_Defaults(t *testing.T) {
 	if cfg.Split.FooterFile != "" {
 		t.Errorf("Split.FooterFile = %s, want empty string", cfg.Split.FooterFile)
 	}
+
+	// Check Terraform file defaults
+	if cfg.TerraformFiles == nil {
+		t.Fatal("TerraformFiles config is nil")
+	}
+	if len(cfg.TerraformFiles.Include) != 2 ||
+		cfg.TerraformFiles.Include[0] != "*.tf" || cfg.TerraformFiles.Include[1] != "*.tofu" {
+		t.Errorf("TerraformFiles.Include = %v, want [*.tf *.tofu]", cfg.TerraformFiles.Include)
+	}
+	if len(cfg.TerraformFiles.Exclude) != 0 {
+		t.Errorf("TerraformFiles.Exclude = %v, want empty", cfg.TerraformFiles.Exclude)
+	}
+
+	// Check export defaults
+	if cfg.Export == nil || cfg.Export.KeepOrphans {
+		t.Errorf("Export = %+v, want KeepOrphans false", cfg.Export)
+	}
 }
 
 func TestLoad_FromConfigFile(t *testing.T) {
@@ -52,6 +69,11 @@ func TestLoad_FromConfigFile(t *testing.T) {
 
 	configContent := `export_path: custom_docs
 docs_file: VARIABLES.md
+terraform_files:
+  include: ["*.tf"]
+  exclude: ["examples_*.tf"]
+export:
+  keep_orphans: true
 split:
   input_path: docs/all_vars.md
   output_dir: split_vars
@@ -101,6 +123,18 @@ split:
 	if cfg.Split.FooterFile != "templates/_footer.md" {
 		t.Errorf("Split.FooterFile = %s, want templates/_footer.md", cfg.Split.FooterFile)
 	}
+
+	// Check Terraform file patterns
+	if len(cfg.TerraformFiles.Include) != 1 || cfg.TerraformFiles.Include[0] != "*.tf" {
+		t.Errorf("TerraformFiles.Include = %v, want [*.tf]", cfg.TerraformFiles.Include)
+	}
+	if len(cfg.TerraformFiles.Exclude) != 1 || cfg.TerraformFiles.Exclude[0] != "examples_*.tf" {
+		t.Errorf("TerraformFiles.Exclude = %v, want [examples_*.tf]", cfg.TerraformFiles.Exclude)
+	}
+
+	if cfg.Export == nil || !cfg.Export.KeepOrphans {
+		t.Errorf("Export = %+v, want KeepOrphans true", cfg.Export)
+	}
 }
 
 func TestSetDefaults(t *testing.T) {
@@ -118,3 +152,73 @@ func TestSetDefaults(t *testing.T) {
 		t.Errorf("Default split.output_dir not set correctly")
 	}
 }
+
+func TestLoadFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, ".marinated.yml")
+	if err := os.WriteFile(configFile, []byte("export_path: module_docs\n"), 0600); err != nil {
+		t.Fatalf("Failed to create config file: %v", err)
+	}
+
+	// The global viper state must not leak into a per-module config
+	viper.Reset()
+	config.SetDefaults()
+	viper.Set("docs_file", "GLOBAL.md")
+
+	cfg, err := config.LoadFile(configFile)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	if cfg.ExportPath != "module_docs" {
+		t.Errorf("ExportPath = %s, want module_docs", cfg.ExportPath)
+	}
+	if cfg.DocsFile != "README.md" {
+		t.Errorf("DocsFile = %s, want README.md", cfg.DocsFile)
+	}
+	if cfg.TerraformFiles == nil || len(cfg.TerraformFiles.Include) != 2 {
+		t.Errorf("TerraformFiles = %v, want defaults", cfg.TerraformFiles)
+	}
+
+	if _, err := config.LoadFile(filepath.Join(tmpDir, "missing.yml")); err == nil {
+		t.Error("LoadFile() expected error for missing file")
+	}
+}
+
+func TestFindNearest(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "modules", "network", "subnets")
+	if err := os.MkdirAll(filepath.Join(root, "modules", "network", ".config"), 0750); err != nil {
+		t.Fatalf("Failed to create directories: %v", err)
+	}
+	if err := os.MkdirAll(nested, 0750); err != nil {
+		t.Fatalf("Failed to create directories: %v", err)
+	}
+
+	rootConfig := filepath.Join(root, ".marinated.yml")
+	moduleConfig := filepath.Join(root, "modules", "network", ".config", ".marinated.yml")
+	for _, path := range []string{rootConfig, moduleConfig} {
+		if err := os.WriteFile(path, []byte("export_path: docs\n"), 0600); err != nil {
+			t.Fatalf("Failed to create config file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		dir    string
+		stopAt string
+		want   string
+	}{
+		{name: "config in .config of ancestor", dir: nested, stopAt: root, want: moduleConfig},
+		{name: "config in root", dir: filepath.Join(root, "modules"), stopAt: root, want: rootConfig},
+		{name: "search stops at stopAt", dir: filepath.Join(root, "modules"), stopAt: filepath.Join(root, "modules"), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := config.FindNearest(tt.dir, tt.stopAt); got != tt.want {
+				t.Errorf("FindNearest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
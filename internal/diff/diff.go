@@ -0,0 +1,174 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change.
+const contextLines = 3
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// edit is a single line of an edit script.
+type edit struct {
+	kind opKind
+	line string // Includes the trailing newline, if any
+}
+
+// Unified returns a unified diff turning from into to, labelled with fromName and toName.
+// Returns an empty string if the texts are equal.
+func Unified(fromName, toName, from, to string) string {
+	if from == to {
+		return ""
+	}
+
+	edits := computeEdits(splitLines(from), splitLines(to))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+	writeHunks(&b, edits)
+	return b.String()
+}
+
+// splitLines splits text into lines, keeping line endings.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// computeEdits returns the shortest edit script between a and b.
+// Common leading and trailing lines are stripped before running the LCS,
+// which keeps the table small for the typical "one block changed" case.
+func computeEdits(a, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]edit, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		edits = append(edits, edit{kind: opEqual, line: line})
+	}
+	edits = append(edits, lcsEdits(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, edit{kind: opEqual, line: line})
+	}
+	return edits
+}
+
+// lcsEdits computes an edit script from the longest common subsequence of a and b.
+func lcsEdits(a, b []string) []edit {
+	width := len(b) + 1
+	// lcs[i*width+j] is the LCS length of a[i:] and b[j:]
+	lcs := make([]int, (len(a)+1)*width)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else {
+				lcs[i*width+j] = max(lcs[(i+1)*width+j], lcs[i*width+j+1])
+			}
+		}
+	}
+
+	edits := make([]edit, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			edits = append(edits, edit{kind: opEqual, line: a[i]})
+			i++
+			j++
+		case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+			edits = append(edits, edit{kind: opDelete, line: a[i]})
+			i++
+		default:
+			edits = append(edits, edit{kind: opInsert, line: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		edits = append(edits, edit{kind: opDelete, line: a[i]})
+	}
+	for ; j < len(b); j++ {
+		edits = append(edits, edit{kind: opInsert, line: b[j]})
+	}
+	return edits
+}
+
+// writeHunks groups changes that are close together into hunks and writes them.
+func writeHunks(b *strings.Builder, edits []edit) {
+	// Line offsets in from and to before each edit
+	fromPos := make([]int, len(edits)+1)
+	toPos := make([]int, len(edits)+1)
+	var changes []int
+	for idx, e := range edits {
+		fromPos[idx+1], toPos[idx+1] = fromPos[idx], toPos[idx]
+		if e.kind != opInsert {
+			fromPos[idx+1]++
+		}
+		if e.kind != opDelete {
+			toPos[idx+1]++
+		}
+		if e.kind != opEqual {
+			changes = append(changes, idx)
+		}
+	}
+
+	for k := 0; k < len(changes); {
+		first, last := changes[k], changes[k]
+		k++
+		for k < len(changes) && changes[k]-last <= 2*contextLines+1 {
+			last = changes[k]
+			k++
+		}
+
+		lo := max(0, first-contextLines)
+		hi := min(len(edits), last+1+contextLines)
+		writeHunk(b, edits[lo:hi], fromPos[lo], fromPos[hi]-fromPos[lo], toPos[lo], toPos[hi]-toPos[lo])
+	}
+}
+
+// writeHunk writes a single hunk with its range header.
+func writeHunk(b *strings.Builder, edits []edit, fromStart, fromCount, toStart, toCount int) {
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", hunkRange(fromStart, fromCount), hunkRange(toStart, toCount))
+	for _, e := range edits {
+		switch e.kind {
+		case opEqual:
+			b.WriteString(" ")
+		case opDelete:
+			b.WriteString("-")
+		case opInsert:
+			b.WriteString("+")
+		}
+		b.WriteString(e.line)
+		if !strings.HasSuffix(e.line, "\n") {
+			b.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange formats a hunk range; empty ranges refer to the line before the change.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,55 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/glueckkanja/marinatemd/internal/diff"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{
+			name: "equal",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+			want: "",
+		},
+		{
+			name: "changed line with context",
+			from: "1\n2\n3\n4\n5\n6\n7\n8\n",
+			to:   "1\n2\n3\n4\nfive\n6\n7\n8\n",
+			want: "--- old\n+++ new\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "new file",
+			from: "",
+			to:   "a\n",
+			want: "--- old\n+++ new\n@@ -0,0 +1,1 @@\n+a\n",
+		},
+		{
+			name: "separate hunks",
+			from: "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n",
+			to:   "A\n1\n2\n3\n4\n5\n6\n7\n8\nB\n",
+			want: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n@@ -7,4 +7,4 @@\n 6\n 7\n 8\n-b\n+B\n",
+		},
+		{
+			name: "missing trailing newline",
+			from: "a\nb",
+			to:   "a\nc",
+			want: "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diff.Unified("old", "new", tt.from, tt.to); got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
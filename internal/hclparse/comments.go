@@ -0,0 +1,95 @@
+package hclparse
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// ExtractComments collects the comments in an HCL expression source and attaches them to source lines.
+// Inline comments (following other tokens on the same line) are attached to their own line.
+// Leading comments (on their own lines, directly above a token) are attached to the line of that token.
+// If a line has both, the leading comment comes first.
+//
+// This is used to seed descriptions from comments on object attributes in type constraints:
+//
+//	object({
+//	  # Database hostname
+//	  host = string
+//	  port = number # Database port
+//	})
+func ExtractComments(src []byte, filename string, start hcl.Pos) map[int]string {
+	tokens, _ := hclsyntax.LexExpression(src, filename, start)
+
+	comments := make(map[int]string)
+	var pending []string
+	pendingEndLine := 0
+	lastTokenLine := 0
+
+	for _, token := range tokens {
+		switch token.Type {
+		case hclsyntax.TokenNewline, hclsyntax.TokenEOF:
+			continue
+		case hclsyntax.TokenComment:
+			text := cleanComment(string(token.Bytes))
+			if token.Range.Start.Line == lastTokenLine {
+				// Inline comment: belongs to the line it trails
+				appendComment(comments, lastTokenLine, text)
+				continue
+			}
+			// Leading comment: a blank line between comment blocks starts a new block
+			if len(pending) > 0 && token.Range.Start.Line > pendingEndLine+1 {
+				pending = nil
+			}
+			if text != "" {
+				pending = append(pending, text)
+			}
+			pendingEndLine = commentEndLine(token)
+		default:
+			line := token.Range.Start.Line
+			if len(pending) > 0 && line == pendingEndLine+1 {
+				appendComment(comments, line, strings.Join(pending, " "))
+			}
+			pending = nil
+			lastTokenLine = token.Range.End.Line
+		}
+	}
+
+	return comments
+}
+
+// appendComment adds text to the comment for a line.
+func appendComment(comments map[int]string, line int, text string) {
+	if text == "" {
+		return
+	}
+	if existing, ok := comments[line]; ok {
+		comments[line] = existing + " " + text
+		return
+	}
+	comments[line] = text
+}
+
+// commentEndLine returns the last line a comment token occupies.
+// Line comments include their trailing newline, which must not count as a line.
+func commentEndLine(token hclsyntax.Token) int {
+	if strings.HasSuffix(string(token.Bytes), "\n") {
+		return token.Range.End.Line - 1
+	}
+	return token.Range.End.Line
+}
+
+// cleanComment strips comment markers and collapses whitespace.
+func cleanComment(raw string) string {
+	text := strings.TrimSpace(raw)
+	switch {
+	case strings.HasPrefix(text, "/*"):
+		text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+	case strings.HasPrefix(text, "//"):
+		text = strings.TrimPrefix(text, "//")
+	case strings.HasPrefix(text, "#"):
+		text = strings.TrimPrefix(text, "#")
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
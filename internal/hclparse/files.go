@@ -0,0 +1,92 @@
+package hclparse
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// FilePatterns selects which Terraform/OpenTofu files in a module directory are scanned for variables.
+// Patterns use filepath.Match syntax and are relative to the module directory.
+type FilePatterns struct {
+	// Include lists the glob patterns of files to scan.
+	// Default: ["*.tf", "*.tofu"]
+	Include []string `mapstructure:"include" yaml:"include"`
+
+	// Exclude lists glob patterns of files to skip, even if they match an include pattern.
+	// Default: [] (nothing excluded)
+	Exclude []string `mapstructure:"exclude" yaml:"exclude"`
+}
+
+// DefaultFilePatterns returns the default file patterns (all .tf and .tofu files).
+func DefaultFilePatterns() *FilePatterns {
+	return &FilePatterns{
+		Include: []string{"*.tf", "*.tofu"},
+		Exclude: []string{},
+	}
+}
+
+// Files returns the sorted list of files in modulePath that match the include patterns
+// and none of the exclude patterns.
+func (fp *FilePatterns) Files(modulePath string) ([]string, error) {
+	seen := make(map[string]bool)
+	files := make([]string, 0)
+
+	for _, pattern := range fp.Include {
+		matches, err := filepath.Glob(filepath.Join(modulePath, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+		}
+
+		for _, match := range matches {
+			if seen[match] {
+				continue
+			}
+			seen[match] = true
+
+			excluded, excludeErr := fp.isExcluded(modulePath, match)
+			if excludeErr != nil {
+				return nil, excludeErr
+			}
+			if !excluded {
+				files = append(files, match)
+			}
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// isExcluded reports whether a file matches one of the exclude patterns.
+func (fp *FilePatterns) isExcluded(modulePath, file string) (bool, error) {
+	relPath, err := filepath.Rel(modulePath, file)
+	if err != nil {
+		relPath = filepath.Base(file)
+	}
+
+	for _, pattern := range fp.Exclude {
+		matched, matchErr := filepath.Match(pattern, relPath)
+		if matchErr != nil {
+			return false, fmt.Errorf("invalid exclude pattern %q: %w", pattern, matchErr)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Validate checks that all patterns are well-formed.
+func (fp *FilePatterns) Validate() error {
+	if len(fp.Include) == 0 {
+		return errors.New("terraform_files.include must contain at least one pattern")
+	}
+	for _, pattern := range append(append([]string{}, fp.Include...), fp.Exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid terraform_files pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
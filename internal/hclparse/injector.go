@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
 	"strings"
 )
@@ -12,19 +11,31 @@ import (
 // TerraformInjector handles injecting markdown documentation into Terraform variable files.
 type TerraformInjector struct {
 	modulePath string
+	patterns   *FilePatterns
 }
 
-// NewTerraformInjector creates a new Terraform injector for the given module path.
+// NewTerraformInjector creates a new Terraform injector for the given module path
+// that scans all .tf and .tofu files.
 func NewTerraformInjector(modulePath string) *TerraformInjector {
+	return NewTerraformInjectorWithFilePatterns(modulePath, nil)
+}
+
+// NewTerraformInjectorWithFilePatterns creates a new Terraform injector for the given module path
+// that scans the files selected by patterns.
+func NewTerraformInjectorWithFilePatterns(modulePath string, patterns *FilePatterns) *TerraformInjector {
+	if patterns == nil {
+		patterns = DefaultFilePatterns()
+	}
 	return &TerraformInjector{
 		modulePath: modulePath,
+		patterns:   patterns,
 	}
 }
 
 // FindVariableFile locates the Terraform file containing a variable with the given marinated ID.
 // Returns the file path and the variable name, or an error if not found.
 func (ti *TerraformInjector) FindVariableFile(marinatedID string) (string, string, error) {
-	parser := NewParser()
+	parser := NewParserWithFilePatterns(ti.patterns)
 	if err := parser.ParseVariables(ti.modulePath); err != nil {
 		return "", "", fmt.Errorf("failed to parse variables: %w", err)
 	}
@@ -36,36 +47,13 @@ func (ti *TerraformInjector) FindVariableFile(marinatedID string) (string, strin
 
 	for _, v := range marinatedVars {
 		if v.MarinatedID == marinatedID {
-			// Find the file containing this variable
-			pattern := filepath.Join(ti.modulePath, "variables*.tf")
-			matches, globErr := filepath.Glob(pattern)
-			if globErr != nil {
-				return "", "", fmt.Errorf("failed to glob for variables files: %w", globErr)
-			}
-
-			for _, filename := range matches {
-				if containsVariableDefinition(filename, v.Name) {
-					return filename, v.Name, nil
-				}
-			}
+			return v.File, v.Name, nil
 		}
 	}
 
 	return "", "", fmt.Errorf("variable with marinated ID %s not found", marinatedID)
 }
 
-// containsVariableDefinition checks if a file contains a variable definition.
-func containsVariableDefinition(filename, variableName string) bool {
-	content, err := os.ReadFile(filename)
-	if err != nil {
-		return false
-	}
-
-	// Look for variable "name" { pattern
-	pattern := fmt.Sprintf("variable \"%s\"", variableName)
-	return strings.Contains(string(content), pattern)
-}
-
 // InjectIntoFile injects markdown documentation inside the description string of a Terraform variable.
 // It looks for the MARINATED marker in the description and injects content inside the description using HTML comments.
 func (ti *TerraformInjector) InjectIntoFile(filePath, marinatedID, markdownContent string) error {
@@ -74,7 +62,20 @@ func (ti *TerraformInjector) InjectIntoFile(filePath, marinatedID, markdownConte
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
-	fileContent := string(content)
+	modified, err := ti.Inject(string(content), marinatedID, markdownContent)
+	if err != nil {
+		return err
+	}
+
+	if writeErr := os.WriteFile(filePath, []byte(modified), 0600); writeErr != nil {
+		return fmt.Errorf("failed to write file: %w", writeErr)
+	}
+	return nil
+}
+
+// Inject injects markdown documentation into the variable description in fileContent
+// and returns the result. It is the in-memory counterpart of InjectIntoFile.
+func (ti *TerraformInjector) Inject(fileContent, marinatedID, markdownContent string) (string, error) {
 	// Handle both escaped and unescaped underscores in markers
 	escapedID := strings.ReplaceAll(marinatedID, "_", `\_`)
 	startComment := fmt.Sprintf("<!-- MARINATED: %s -->", marinatedID)
@@ -84,7 +85,7 @@ func (ti *TerraformInjector) InjectIntoFile(filePath, marinatedID, markdownConte
 
 	// Check for either version of the marker
 	if !strings.Contains(fileContent, startComment) && !strings.Contains(fileContent, escapedStartComment) {
-		return fmt.Errorf("MARINATED marker %s not found in file", startComment)
+		return "", fmt.Errorf("MARINATED marker %s not found in file", startComment)
 	}
 
 	// Use the version that exists in the file
@@ -95,15 +96,15 @@ func (ti *TerraformInjector) InjectIntoFile(filePath, marinatedID, markdownConte
 		actualEndComment = escapedEndComment
 	}
 
-	modified, err := processFileContent(fileContent, marinatedID, markdownContent, actualStartComment, actualEndComment)
-	if err != nil {
-		return err
-	}
+	// Descriptions are HCL strings; literal "${" and "%{" (e.g. from validation messages) must not be interpolated
+	markdownContent = escapeTemplateSequences(markdownContent)
 
-	if writeErr := os.WriteFile(filePath, []byte(modified), 0600); writeErr != nil {
-		return fmt.Errorf("failed to write file: %w", writeErr)
-	}
-	return nil
+	return processFileContent(fileContent, marinatedID, markdownContent, actualStartComment, actualEndComment)
+}
+
+// escapeTemplateSequences escapes HCL interpolation and directive sequences in s.
+func escapeTemplateSequences(s string) string {
+	return strings.NewReplacer("${", "$${", "%{", "%%{").Replace(s)
 }
 
 func processFileContent(fileContent, marinatedID, markdownContent, startComment, endComment string) (string, error) {
@@ -365,7 +366,7 @@ func getIndentation(line string) string {
 // FindMarkers scans the Terraform module for all MARINATED markers.
 // Returns a slice of marinated IDs found.
 func (ti *TerraformInjector) FindMarkers() ([]string, error) {
-	parser := NewParser()
+	parser := NewParserWithFilePatterns(ti.patterns)
 	if err := parser.ParseVariables(ti.modulePath); err != nil {
 		return nil, fmt.Errorf("failed to parse variables: %w", err)
 	}
@@ -4,13 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 )
 
 // Common errors.
@@ -18,27 +18,34 @@ var (
 	ErrNotImplemented = errors.New("not yet implemented")
 )
 
-// Parser handles parsing of HCL files (variables.tf) to extract variable definitions.
+// Parser handles parsing of HCL files (*.tf, *.tofu) to extract variable definitions.
 type Parser struct {
 	variables []*Variable
+	patterns  *FilePatterns
 }
 
-// NewParser creates a new HCL parser instance.
+// NewParser creates a new HCL parser instance that scans all .tf and .tofu files.
 func NewParser() *Parser {
+	return NewParserWithFilePatterns(nil)
+}
+
+// NewParserWithFilePatterns creates a new HCL parser instance that scans the files selected by patterns.
+func NewParserWithFilePatterns(patterns *FilePatterns) *Parser {
+	if patterns == nil {
+		patterns = DefaultFilePatterns()
+	}
 	return &Parser{
 		variables: make([]*Variable, 0),
+		patterns:  patterns,
 	}
 }
 
-// ParseVariables parses all variables.*.tf files in the given directory
-// ParseVariables scans the module path for variables.tf files
+// ParseVariables scans the module path for Terraform files matching the parser's file patterns
 // and extracts variable definitions, particularly those marked with MARINATED comments.
 func (p *Parser) ParseVariables(modulePath string) error {
-	// Find all variables.*.tf files
-	pattern := filepath.Join(modulePath, "variables*.tf")
-	matches, err := filepath.Glob(pattern)
+	matches, err := p.patterns.Files(modulePath)
 	if err != nil {
-		return fmt.Errorf("failed to glob for variables files: %w", err)
+		return fmt.Errorf("failed to find Terraform files: %w", err)
 	}
 
 	parser := hclparse.NewParser()
@@ -69,10 +76,11 @@ func (p *Parser) ParseVariables(modulePath string) error {
 				continue
 			}
 
-			variable, parseErr := p.parseVariableBlock(block)
+			variable, parseErr := p.parseVariableBlock(block, fileContent)
 			if parseErr != nil {
 				return fmt.Errorf("failed to parse variable %s in %s: %w", block.Labels[0], filename, parseErr)
 			}
+			variable.File = filename
 
 			p.variables = append(p.variables, variable)
 		}
@@ -82,20 +90,24 @@ func (p *Parser) ParseVariables(modulePath string) error {
 }
 
 // parseVariableBlock extracts a Variable from an HCL variable block.
-func (p *Parser) parseVariableBlock(block *hclsyntax.Block) (*Variable, error) {
+// The file content is needed to recover comments, which are not part of the syntax tree.
+func (p *Parser) parseVariableBlock(block *hclsyntax.Block, fileContent []byte) (*Variable, error) {
 	varName := block.Labels[0]
 
 	variable := &Variable{
-		Name: varName,
+		Name:     varName,
+		Nullable: true, // Terraform's default
 	}
 
 	// Extract attributes
 	for name, attr := range block.Body.Attributes {
 		switch name {
 		case "type":
-			// Store the raw type expression as a string
-			typeStr := extractTypeString(attr.Expr)
-			variable.Type = typeStr
+			// Keep the parsed expression for type decoding and the raw source for display
+			variable.TypeExpr = attr.Expr
+			variable.Type = extractTypeString(attr.Expr)
+			typeRange := attr.Expr.Range()
+			variable.TypeComments = ExtractComments(typeRange.SliceBytes(fileContent), typeRange.Filename, typeRange.Start)
 
 		case "description":
 			// Extract description value
@@ -109,10 +121,34 @@ func (p *Parser) parseVariableBlock(block *hclsyntax.Block) (*Variable, error) {
 
 		case "default":
 			// Extract default value
+			variable.HasDefault = true
 			val, diags := attr.Expr.Value(nil)
 			if !diags.HasErrors() && !val.IsNull() {
-				variable.Default = extractCtyValue(val)
+				variable.Default = ExtractCtyValue(val)
+			}
+
+		case "sensitive", "nullable", "ephemeral":
+			// Like Terraform, accept any constant that converts to bool, e.g. "true"
+			val, diags := attr.Expr.Value(nil)
+			val, err := convert.Convert(val, cty.Bool)
+			if diags.HasErrors() || err != nil || !val.IsKnown() || val.IsNull() {
+				return nil, fmt.Errorf("%s: %s must be a constant bool", attr.Range(), name)
 			}
+			switch name {
+			case "sensitive":
+				variable.Sensitive = val.True()
+			case "nullable":
+				variable.Nullable = val.True()
+			case "ephemeral":
+				variable.Ephemeral = val.True()
+			}
+		}
+	}
+
+	// Extract validation blocks in source order
+	for _, nested := range block.Body.Blocks {
+		if nested.Type == "validation" {
+			variable.Validations = append(variable.Validations, parseValidationBlock(varName, nested, fileContent))
 		}
 	}
 
@@ -184,12 +220,25 @@ func reconstructTypeExpr(expr hclsyntax.Expression) string {
 
 // Variable represents a parsed Terraform/OpenTofu variable.
 type Variable struct {
-	Name        string
-	Type        string // HCL type expression
-	Description string
-	Default     any
-	Marinated   bool   // Whether this variable has a MARINATED marker
-	MarinatedID string // The ID after "MARINATED:" in the description
+	Name         string
+	Type         string               // HCL type expression source
+	TypeExpr     hclsyntax.Expression // Parsed type expression (nil if the variable has no type)
+	TypeComments map[int]string       // Comments inside the type expression by source line (see ExtractComments)
+	Description  string
+	Default      any
+	HasDefault   bool          // The variable declares a default, even null; it is optional then
+	Validations  []*Validation // Validation blocks in source order
+	Sensitive    bool          // sensitive = true: values are redacted in plans and outputs
+	Nullable     bool          // false if nullable = false; null is then replaced by the default
+	Ephemeral    bool          // ephemeral = true: the value is never persisted in state or plans
+	File         string        // Path of the file declaring the variable
+	Marinated    bool          // Whether this variable has a MARINATED marker
+	MarinatedID  string        // The ID after "MARINATED:" in the description
+}
+
+// Variables returns all parsed variables, marinated or not.
+func (p *Parser) Variables() []*Variable {
+	return p.variables
 }
 
 // ExtractMarinatedVars returns only variables marked with MARINATED comments.
@@ -218,9 +267,9 @@ func ExtractMarinatedID(description string) (string, bool) {
 	return "", false
 }
 
-// extractCtyValue converts a cty.Value to a Go value (any).
+// ExtractCtyValue converts a cty.Value to a Go value (any).
 // Handles primitive types, lists, sets, maps, and objects, excluding explicit nulls.
-func extractCtyValue(val cty.Value) any {
+func ExtractCtyValue(val cty.Value) any {
 	if val.IsNull() {
 		return nil
 	}
@@ -246,7 +295,7 @@ func extractCtyValue(val cty.Value) any {
 		it := val.ElementIterator()
 		for it.Next() {
 			_, elemVal := it.Element()
-			result = append(result, extractCtyValue(elemVal))
+			result = append(result, ExtractCtyValue(elemVal))
 		}
 		return result
 	case typ.IsMapType() || typ.IsObjectType():
@@ -255,7 +304,7 @@ func extractCtyValue(val cty.Value) any {
 		for it.Next() {
 			keyVal, elemVal := it.Element()
 			key := keyVal.AsString()
-			result[key] = extractCtyValue(elemVal)
+			result[key] = ExtractCtyValue(elemVal)
 		}
 		return result
 	}
@@ -3,9 +3,11 @@ package hclparse_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/glueckkanja/marinatemd/internal/hclparse"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // Helper function to set up test parser with HCL content.
@@ -97,6 +99,9 @@ variable "app_config" {
 	if v.Type == "" {
 		t.Error("expected Type to be set")
 	}
+	if v.TypeExpr == nil {
+		t.Error("expected TypeExpr to be set")
+	}
 }
 
 func TestParser_MapOfObjects(t *testing.T) {
@@ -224,3 +229,447 @@ variable "broken" {
 		t.Error("ParseVariables() expected error for invalid HCL, got nil")
 	}
 }
+
+func TestParser_TypeComments(t *testing.T) {
+	hclContent := `
+variable "app_config" {
+  type = object({
+    # Database connection settings.
+    # Omit to run in memory.
+    database = optional(object({
+      host = string # DB hostname
+      /* Port number */
+      port = optional(number, 5432)
+    }))
+
+    # Detached comment
+
+    name = string
+  })
+  description = "<!-- MARINATED: app_config -->"
+}
+`
+	p, err := setupTestParser(t, hclContent)
+	if err != nil {
+		t.Fatalf("ParseVariables() error = %v", err)
+	}
+
+	vars, err := p.ExtractMarinatedVars()
+	if err != nil {
+		t.Fatalf("ExtractMarinatedVars() error = %v", err)
+	}
+	if len(vars) != 1 {
+		t.Fatalf("expected 1 variable, got %d", len(vars))
+	}
+
+	want := map[int]string{
+		6: "Database connection settings. Omit to run in memory.",
+		7: "DB hostname",
+		9: "Port number",
+	}
+	got := vars[0].TypeComments
+	if len(got) != len(want) {
+		t.Errorf("expected %d comments, got %d: %v", len(want), len(got), got)
+	}
+	for line, text := range want {
+		if got[line] != text {
+			t.Errorf("comment on line %d = %q, want %q", line, got[line], text)
+		}
+	}
+}
+
+func TestParser_Validations(t *testing.T) {
+	hclContent := `
+variable "app_config" {
+  type        = object({ database = object({ port = number, host = string }), name = string })
+  description = "<!-- MARINATED: app_config -->"
+
+  validation {
+    condition     = var.app_config.database.port > 0 && var.app_config.database.port < 65536
+    error_message = "Port must be between 1 and 65535."
+  }
+
+  validation {
+    condition     = var.app_config.database.host != "" || var.app_config.database.port == 0
+    error_message = "Host is required, got ${var.app_config.database.host}."
+  }
+
+  validation {
+    condition     = length(var.app_config.name) > 0 && var.app_config != null
+    error_message = "Name must not be empty."
+  }
+}
+`
+	p, err := setupTestParser(t, hclContent)
+	if err != nil {
+		t.Fatalf("ParseVariables() error = %v", err)
+	}
+
+	vars, err := p.ExtractMarinatedVars()
+	if err != nil {
+		t.Fatalf("ExtractMarinatedVars() error = %v", err)
+	}
+	if len(vars) != 1 {
+		t.Fatalf("expected 1 variable, got %d", len(vars))
+	}
+
+	want := []hclparse.Validation{
+		{
+			Condition:    "var.app_config.database.port > 0 && var.app_config.database.port < 65536",
+			ErrorMessage: "Port must be between 1 and 65535.",
+			Path:         []string{"database", "port"},
+		},
+		{
+			Condition:    `var.app_config.database.host != "" || var.app_config.database.port == 0`,
+			ErrorMessage: "Host is required, got ${var.app_config.database.host}.",
+			Path:         []string{"database"},
+		},
+		{
+			Condition:    "length(var.app_config.name) > 0 && var.app_config != null",
+			ErrorMessage: "Name must not be empty.",
+		},
+	}
+
+	got := vars[0].Validations
+	if len(got) != len(want) {
+		t.Fatalf("expected %d validations, got %d", len(want), len(got))
+	}
+	for i, w := range want {
+		if got[i].Condition != w.Condition {
+			t.Errorf("validation %d condition = %q, want %q", i, got[i].Condition, w.Condition)
+		}
+		if got[i].ErrorMessage != w.ErrorMessage {
+			t.Errorf("validation %d error message = %q, want %q", i, got[i].ErrorMessage, w.ErrorMessage)
+		}
+		if strings.Join(got[i].Path, ".") != strings.Join(w.Path, ".") {
+			t.Errorf("validation %d path = %v, want %v", i, got[i].Path, w.Path)
+		}
+	}
+}
+
+func TestParser_ValidationValueRules(t *testing.T) {
+	hclContent := `
+variable "app" {
+  type        = object({ mode = optional(string), name = string, count = number, tags = list(string) })
+  description = "<!-- MARINATED: app -->"
+
+  validation {
+    condition     = var.app.mode == null || contains(["dev", "prod"], var.app.mode)
+    error_message = "Invalid mode."
+  }
+
+  validation {
+    condition     = can(regex("^[a-z]+$", var.app.name)) && (var.app.count >= 1 && 10 >= var.app.count)
+    error_message = "Invalid name or count."
+  }
+
+  validation {
+    condition     = contains(["a"], var.app.tags[0]) || var.app.count > 0
+    error_message = "Not recognised."
+  }
+}
+`
+	p, err := setupTestParser(t, hclContent)
+	if err != nil {
+		t.Fatalf("ParseVariables() error = %v", err)
+	}
+
+	vars, err := p.ExtractMarinatedVars()
+	if err != nil {
+		t.Fatalf("ExtractMarinatedVars() error = %v", err)
+	}
+	validations := vars[0].Validations
+	if len(validations) != 3 {
+		t.Fatalf("expected 3 validations, got %d", len(validations))
+	}
+
+	mode := validations[0].Rules
+	if len(mode) != 1 || strings.Join(mode[0].Path, ".") != "mode" || len(mode[0].AllowedValues) != 2 ||
+		mode[0].AllowedValues[0] != "dev" || mode[0].AllowedValues[1] != "prod" {
+		t.Errorf("expected allowed values [dev prod] for mode, got %+v", mode)
+	}
+
+	rules := validations[1].Rules
+	if len(rules) != 2 {
+		t.Fatalf("expected rules for name and count, got %d", len(rules))
+	}
+	if strings.Join(rules[0].Path, ".") != "name" || rules[0].Pattern != "^[a-z]+$" {
+		t.Errorf("expected pattern for name, got %+v", rules[0])
+	}
+	if strings.Join(rules[1].Path, ".") != "count" || rules[1].Min == nil || *rules[1].Min != 1 ||
+		rules[1].Max == nil || *rules[1].Max != 10 {
+		t.Errorf("expected count range 1..10, got %+v", rules[1])
+	}
+
+	if len(validations[2].Rules) != 0 {
+		t.Errorf("expected no rules for element references and unguarded ||, got %+v", validations[2].Rules)
+	}
+}
+
+func TestParser_VariableAttributes(t *testing.T) {
+	hclContent := `
+variable "db_password" {
+  type        = string
+  description = "<!-- MARINATED: db_password -->"
+  sensitive   = "true"
+  nullable    = false
+  ephemeral   = true
+}
+
+variable "app_name" {
+  type        = string
+  description = "<!-- MARINATED: app_name -->"
+}
+`
+	p, err := setupTestParser(t, hclContent)
+	if err != nil {
+		t.Fatalf("ParseVariables() error = %v", err)
+	}
+
+	vars, err := p.ExtractMarinatedVars()
+	if err != nil {
+		t.Fatalf("ExtractMarinatedVars() error = %v", err)
+	}
+	if len(vars) != 2 {
+		t.Fatalf("expected 2 variables, got %d", len(vars))
+	}
+
+	for _, v := range vars {
+		switch v.Name {
+		case "db_password":
+			if !v.Sensitive || v.Nullable || !v.Ephemeral {
+				t.Errorf("db_password: sensitive=%v nullable=%v ephemeral=%v, want true false true",
+					v.Sensitive, v.Nullable, v.Ephemeral)
+			}
+		case "app_name":
+			if v.Sensitive || !v.Nullable || v.Ephemeral {
+				t.Errorf("app_name: sensitive=%v nullable=%v ephemeral=%v, want Terraform defaults false true false",
+					v.Sensitive, v.Nullable, v.Ephemeral)
+			}
+		}
+	}
+}
+
+func TestParser_VariableAttributesNotBool(t *testing.T) {
+	hclContent := `
+variable "db_password" {
+  type      = string
+  sensitive = "yes"
+}
+`
+	_, err := setupTestParser(t, hclContent)
+	if err == nil {
+		t.Fatal("expected an error for a sensitive value that is not a bool")
+	}
+	if !strings.Contains(err.Error(), "variables.tf:4,") || !strings.Contains(err.Error(), "sensitive must be a constant bool") {
+		t.Errorf("expected the error to name the attribute and its range, got: %v", err)
+	}
+}
+
+func TestParser_FilePatterns(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"main.tf": `
+variable "from_main" {
+  type        = string
+  description = "<!-- MARINATED: from_main -->"
+}
+`,
+		"inputs.tofu": `
+variable "from_tofu" {
+  type        = string
+  description = "<!-- MARINATED: from_tofu -->"
+}
+`,
+		"legacy_variables.tf": `
+variable "from_legacy" {
+  type        = string
+  description = "<!-- MARINATED: from_legacy -->"
+}
+`,
+		"notes.txt": `variable "ignored" {}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write test file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		patterns *hclparse.FilePatterns
+		want     []string
+	}{
+		{
+			name:     "defaults scan all tf and tofu files",
+			patterns: nil,
+			want:     []string{"from_tofu", "from_legacy", "from_main"},
+		},
+		{
+			name: "exclude pattern",
+			patterns: &hclparse.FilePatterns{
+				Include: []string{"*.tf", "*.tofu"},
+				Exclude: []string{"legacy_*.tf"},
+			},
+			want: []string{"from_tofu", "from_main"},
+		},
+		{
+			name:     "include pattern",
+			patterns: &hclparse.FilePatterns{Include: []string{"main.tf"}},
+			want:     []string{"from_main"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := hclparse.NewParserWithFilePatterns(tt.patterns)
+			if err := p.ParseVariables(tmpDir); err != nil {
+				t.Fatalf("ParseVariables() error = %v", err)
+			}
+
+			vars, err := p.ExtractMarinatedVars()
+			if err != nil {
+				t.Fatalf("ExtractMarinatedVars() error = %v", err)
+			}
+			if len(vars) != len(tt.want) {
+				t.Fatalf("expected %d variables, got %d", len(tt.want), len(vars))
+			}
+			for i, v := range vars {
+				if v.Name != tt.want[i] {
+					t.Errorf("variable %d = %s, want %s", i, v.Name, tt.want[i])
+				}
+				if v.File == "" {
+					t.Errorf("expected File to be set for %s", v.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestTerraformInjector_FindVariableFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	mainFile := filepath.Join(tmpDir, "main.tf")
+	content := `
+variable "app_config" {
+  type        = object({ name = string })
+  description = "<!-- MARINATED: app_config -->"
+}
+`
+	if err := os.WriteFile(mainFile, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	injector := hclparse.NewTerraformInjector(tmpDir)
+	file, name, err := injector.FindVariableFile("app_config")
+	if err != nil {
+		t.Fatalf("FindVariableFile() error = %v", err)
+	}
+	if file != mainFile {
+		t.Errorf("file = %s, want %s", file, mainFile)
+	}
+	if name != "app_config" {
+		t.Errorf("name = %s, want app_config", name)
+	}
+}
+
+func TestTerraformInjector_InjectEscapesTemplateSequences(t *testing.T) {
+	content := `variable "app_config" {
+  type        = object({ name = string })
+  description = <<-EOT
+<!-- MARINATED: app_config -->
+  EOT
+}
+`
+	injector := hclparse.NewTerraformInjector(t.TempDir())
+	got, err := injector.Inject(content, "app_config", "- Invalid name ${var.app_config.name}, use %{ if x }y%{ endif }")
+	if err != nil {
+		t.Fatalf("Inject() error = %v", err)
+	}
+
+	if !strings.Contains(got, "- Invalid name $${var.app_config.name}, use %%{ if x }y%%{ endif }") {
+		t.Errorf("expected template sequences to be escaped, got:\n%s", got)
+	}
+}
+
+// TestParseVarsFile tests that tfvars values are parsed in source order and that
+// RangeOf locates nested attributes and elements.
+func TestParseVarsFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "prod.tfvars")
+	content := `region = "westeurope"
+database = {
+  name    = "orders"
+  ssl_mod = "require"
+  replicas = [
+    { zone = 1 },
+  ]
+}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	varsFile, err := hclparse.ParseVarsFile(path)
+	if err != nil {
+		t.Fatalf("ParseVarsFile() error = %v", err)
+	}
+	if len(varsFile.Values) != 2 || varsFile.Values[0].Name != "region" || varsFile.Values[1].Name != "database" {
+		t.Fatalf("unexpected values: %+v", varsFile.Values)
+	}
+
+	database := varsFile.Values[1]
+	if got := database.Value.GetAttr("ssl_mod").AsString(); got != "require" {
+		t.Errorf("ssl_mod = %q, want %q", got, "require")
+	}
+
+	tests := []struct {
+		name string
+		path cty.Path
+		line int
+	}{
+		{name: "variable", path: nil, line: 2},
+		{name: "attribute", path: cty.GetAttrPath("ssl_mod"), line: 4},
+		{name: "nested element", path: cty.GetAttrPath("replicas").IndexInt(0).GetAttr("zone"), line: 6},
+		{name: "missing attribute", path: cty.GetAttrPath("missing").GetAttr("x"), line: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := database.RangeOf(tt.path).Start.Line; got != tt.line {
+				t.Errorf("RangeOf() line = %d, want %d", got, tt.line)
+			}
+		})
+	}
+
+	jsonPath := filepath.Join(tmpDir, "dev.tfvars.json")
+	jsonContent := `{
+  "region": "northeurope",
+  "database": {
+    "name": "orders",
+    "ssl_mod": "require",
+    "replicas": [
+      {"zone": 1}
+    ]
+  }
+}
+`
+	if writeErr := os.WriteFile(jsonPath, []byte(jsonContent), 0644); writeErr != nil {
+		t.Fatalf("failed to write test file: %v", writeErr)
+	}
+	jsonFile, err := hclparse.ParseVarsFile(jsonPath)
+	if err != nil {
+		t.Fatalf("ParseVarsFile() error = %v", err)
+	}
+	if len(jsonFile.Values) != 2 || jsonFile.Values[0].Value.AsString() != "northeurope" {
+		t.Fatalf("unexpected JSON values: %+v", jsonFile.Values)
+	}
+
+	// JSON values are located the same way; the JSON file has everything one line further down
+	database = jsonFile.Values[1]
+	for _, tt := range tests {
+		t.Run("json "+tt.name, func(t *testing.T) {
+			if got := database.RangeOf(tt.path).Start.Line; got != tt.line+1 {
+				t.Errorf("RangeOf() line = %d, want %d", got, tt.line+1)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package hclparse
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// VarsFilePatterns returns the patterns of variable definitions files in a module directory:
+// *.tfvars (including *.auto.tfvars) and *.tfvars.json.
+func VarsFilePatterns() *FilePatterns {
+	return &FilePatterns{
+		Include: []string{"*.tfvars", "*.tfvars.json"},
+		Exclude: []string{},
+	}
+}
+
+// VarsFile is a parsed variable definitions file (*.tfvars or *.tfvars.json).
+type VarsFile struct {
+	Path   string
+	Values []*VarValue // In source order
+}
+
+// VarValue is a variable value set in a variable definitions file.
+type VarValue struct {
+	Name  string
+	Value cty.Value
+	Range hcl.Range // Range of the whole definition, name = value
+	expr  hcl.Expression
+}
+
+// ParseVarsFile parses a variable definitions file. Files ending in .json are parsed as JSON,
+// all others as HCL native syntax. Values must be literals; references and function calls
+// are reported as errors, like Terraform does.
+func ParseVarsFile(path string) (*VarsFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
+	}
+
+	parser := hclparse.NewParser()
+	var file *hcl.File
+	var diags hcl.Diagnostics
+	if strings.HasSuffix(path, ".json") {
+		file, diags = parser.ParseJSON(content, path)
+	} else {
+		file, diags = parser.ParseHCL(content, path)
+	}
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, diags)
+	}
+
+	attrs, diags := file.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, diags)
+	}
+
+	varsFile := &VarsFile{Path: path, Values: make([]*VarValue, 0, len(attrs))}
+	for name, attr := range attrs {
+		val, valDiags := attr.Expr.Value(nil)
+		if valDiags.HasErrors() {
+			return nil, fmt.Errorf("invalid value for %s in %s: %w", name, path, valDiags)
+		}
+		varsFile.Values = append(varsFile.Values, &VarValue{
+			Name:  name,
+			Value: val,
+			Range: attr.Range,
+			expr:  attr.Expr,
+		})
+	}
+	sort.Slice(varsFile.Values, func(i, j int) bool {
+		return varsFile.Values[i].Range.Start.Byte < varsFile.Values[j].Range.Start.Byte
+	})
+
+	return varsFile, nil
+}
+
+// RangeOf returns the source range of the part of the value at path, e.g. the value of
+// an object attribute or a list element, in HCL and JSON files alike. Where the path leaves
+// the written value, the range of the innermost part that could be found is returned.
+func (v *VarValue) RangeOf(path cty.Path) hcl.Range {
+	rng := v.Range
+	expr := v.expr
+
+	for _, step := range path {
+		if expr = stepExpr(expr, step); expr == nil {
+			break
+		}
+		rng = expr.Range()
+	}
+	return rng
+}
+
+// stepExpr returns the expression of the object attribute or tuple element addressed by step,
+// or nil if expr is not an object or tuple containing it.
+func stepExpr(expr hcl.Expression, step cty.PathStep) hcl.Expression {
+	var key cty.Value
+	switch s := step.(type) {
+	case cty.GetAttrStep:
+		key = cty.StringVal(s.Name)
+	case cty.IndexStep:
+		key = s.Key
+	default:
+		return nil
+	}
+
+	switch key.Type() {
+	case cty.String:
+		pairs, diags := hcl.ExprMap(expr)
+		if diags.HasErrors() {
+			return nil
+		}
+		for _, pair := range pairs {
+			pairKey, keyDiags := pair.Key.Value(nil)
+			if keyDiags.HasErrors() || pairKey.Type() != cty.String || !pairKey.IsKnown() || pairKey.IsNull() {
+				continue
+			}
+			if pairKey.AsString() == key.AsString() {
+				return pair.Value
+			}
+		}
+	case cty.Number:
+		elems, diags := hcl.ExprList(expr)
+		if diags.HasErrors() {
+			return nil
+		}
+		index, accuracy := key.AsBigFloat().Int64()
+		if accuracy == 0 && index >= 0 && index < int64(len(elems)) {
+			return elems[index]
+		}
+	}
+	return nil
+}
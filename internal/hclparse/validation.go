@@ -0,0 +1,264 @@
+package hclparse
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Validation is a validation block of a variable.
+type Validation struct {
+	Condition    string // Source of the condition expression
+	ErrorMessage string // The error message, or its template source if it interpolates values
+
+	// Path is the attribute path below the variable that the condition is about: the longest
+	// common prefix of all var.<name>.<path> references. Nil if the condition is about the whole variable.
+	Path []string
+
+	// Rules are the value constraints recognised in the condition, see ValueRule.
+	Rules []*ValueRule
+}
+
+// ValueRule is a constraint on the value of one attribute, recognised from a common validation idiom:
+//
+//	contains(["a", "b"], var.x.mode)      -> AllowedValues
+//	can(regex("^[a-z]+$", var.x.name))    -> Pattern
+//	var.x.count >= 1 && var.x.count <= 10 -> Min, Max
+//
+// Idioms are only recognised at the top level of a condition, in operands of &&, and next to
+// a null check in || (var.x.mode == null || contains(...)).
+type ValueRule struct {
+	Path          []string // Attribute path below the variable; empty for the variable itself
+	AllowedValues []any
+	Pattern       string
+	Min           *float64
+	Max           *float64
+}
+
+// parseValidationBlock extracts the condition and error message of a validation block.
+func parseValidationBlock(varName string, block *hclsyntax.Block, fileContent []byte) *Validation {
+	validation := &Validation{}
+
+	if attr, ok := block.Body.Attributes["condition"]; ok {
+		validation.Condition = strings.TrimSpace(string(attr.Expr.Range().SliceBytes(fileContent)))
+		validation.Path = validationPath(varName, attr.Expr)
+		validation.Rules = valueRules(varName, attr.Expr)
+	}
+
+	if attr, ok := block.Body.Attributes["error_message"]; ok {
+		validation.ErrorMessage = errorMessage(attr.Expr, fileContent)
+	}
+
+	return validation
+}
+
+// errorMessage evaluates a literal error message. Messages that interpolate values
+// cannot be evaluated statically and are returned as written, without the quotes.
+func errorMessage(expr hclsyntax.Expression, fileContent []byte) string {
+	val, diags := expr.Value(nil)
+	if !diags.HasErrors() && val.Type() == cty.String && val.IsKnown() && !val.IsNull() {
+		return strings.TrimSpace(val.AsString())
+	}
+
+	source := strings.TrimSpace(string(expr.Range().SliceBytes(fileContent)))
+	if unquoted, ok := strings.CutPrefix(source, `"`); ok {
+		source = strings.TrimSuffix(unquoted, `"`)
+	}
+	return source
+}
+
+// validationPath returns the attribute path that all references to var.<varName> in expr share.
+func validationPath(varName string, expr hclsyntax.Expression) []string {
+	var common []string
+	found := false
+
+	for _, traversal := range hclsyntax.Variables(expr) {
+		if traversal.RootName() != "var" || len(traversal) < 2 {
+			continue
+		}
+		if attr, ok := traversal[1].(hcl.TraverseAttr); !ok || attr.Name != varName {
+			continue
+		}
+
+		path := traversalPath(traversal[2:])
+		if !found {
+			common, found = path, true
+			continue
+		}
+		common = commonPrefix(common, path)
+	}
+
+	if len(common) == 0 {
+		return nil
+	}
+	return common
+}
+
+// traversalPath returns the attribute names at the start of a traversal, up to the first index or splat.
+func traversalPath(traversal hcl.Traversal) []string {
+	var path []string
+	for _, step := range traversal {
+		attr, ok := step.(hcl.TraverseAttr)
+		if !ok {
+			break
+		}
+		path = append(path, attr.Name)
+	}
+	return path
+}
+
+func commonPrefix(a, b []string) []string {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return a[:n]
+}
+
+// valueRules recognises the common validation idioms in a condition, merged per attribute path
+// in order of first appearance.
+func valueRules(varName string, expr hclsyntax.Expression) []*ValueRule {
+	var rules []*ValueRule
+	byPath := make(map[string]*ValueRule)
+
+	ruleFor := func(path []string) *ValueRule {
+		key := strings.Join(path, ".")
+		if rule, ok := byPath[key]; ok {
+			return rule
+		}
+		rule := &ValueRule{Path: path}
+		byPath[key] = rule
+		rules = append(rules, rule)
+		return rule
+	}
+
+	var visit func(expr hclsyntax.Expression)
+	visit = func(expr hclsyntax.Expression) {
+		switch e := expr.(type) {
+		case *hclsyntax.ParenthesesExpr:
+			visit(e.Expression)
+		case *hclsyntax.BinaryOpExpr:
+			if e.Op == hclsyntax.OpLogicalAnd {
+				visit(e.LHS)
+				visit(e.RHS)
+				return
+			}
+			// Optional attributes are usually guarded: var.x.mode == null || contains(...)
+			if e.Op == hclsyntax.OpLogicalOr {
+				if isNullCheck(e.LHS) {
+					visit(e.RHS)
+				} else if isNullCheck(e.RHS) {
+					visit(e.LHS)
+				}
+				return
+			}
+			applyComparison(varName, e, ruleFor)
+		case *hclsyntax.FunctionCallExpr:
+			applyFunctionCall(varName, e, ruleFor)
+		}
+	}
+	visit(expr)
+
+	return rules
+}
+
+// applyFunctionCall recognises contains([...], var.x.path) and can(regex("...", var.x.path)).
+func applyFunctionCall(varName string, call *hclsyntax.FunctionCallExpr, ruleFor func([]string) *ValueRule) {
+	switch {
+	case call.Name == "contains" && len(call.Args) == 2:
+		path, ok := attributePath(varName, call.Args[1])
+		if !ok {
+			return
+		}
+		list, diags := call.Args[0].Value(nil)
+		if diags.HasErrors() || !list.IsWhollyKnown() || list.IsNull() || !list.CanIterateElements() {
+			return
+		}
+		values, _ := ExtractCtyValue(list).([]any)
+		if len(values) > 0 {
+			ruleFor(path).AllowedValues = values
+		}
+
+	case call.Name == "can" && len(call.Args) == 1:
+		regex, ok := call.Args[0].(*hclsyntax.FunctionCallExpr)
+		if !ok || regex.Name != "regex" || len(regex.Args) != 2 {
+			return
+		}
+		path, ok := attributePath(varName, regex.Args[1])
+		if !ok {
+			return
+		}
+		pattern, diags := regex.Args[0].Value(nil)
+		if diags.HasErrors() || pattern.Type() != cty.String || !pattern.IsKnown() || pattern.IsNull() {
+			return
+		}
+		ruleFor(path).Pattern = pattern.AsString()
+	}
+}
+
+// applyComparison recognises var.x.path >= n and var.x.path <= n, in either operand order.
+func applyComparison(varName string, op *hclsyntax.BinaryOpExpr, ruleFor func([]string) *ValueRule) {
+	isMin := op.Op == hclsyntax.OpGreaterThanOrEqual
+	if !isMin && op.Op != hclsyntax.OpLessThanOrEqual {
+		return
+	}
+
+	path, ok := attributePath(varName, op.LHS)
+	bound := op.RHS
+	if !ok {
+		// n <= var.x.path is a minimum, n >= var.x.path a maximum
+		if path, ok = attributePath(varName, op.RHS); !ok {
+			return
+		}
+		bound = op.LHS
+		isMin = !isMin
+	}
+
+	val, diags := bound.Value(nil)
+	if diags.HasErrors() || val.Type() != cty.Number || !val.IsKnown() || val.IsNull() {
+		return
+	}
+	number, _ := val.AsBigFloat().Float64()
+
+	rule := ruleFor(path)
+	if isMin {
+		rule.Min = &number
+	} else {
+		rule.Max = &number
+	}
+}
+
+// isNullCheck reports whether expr compares something with null using ==.
+func isNullCheck(expr hclsyntax.Expression) bool {
+	op, ok := expr.(*hclsyntax.BinaryOpExpr)
+	if !ok || op.Op != hclsyntax.OpEqual {
+		return false
+	}
+	return isNullLiteral(op.LHS) || isNullLiteral(op.RHS)
+}
+
+func isNullLiteral(expr hclsyntax.Expression) bool {
+	literal, ok := expr.(*hclsyntax.LiteralValueExpr)
+	return ok && literal.Val.IsNull()
+}
+
+// attributePath returns the attribute path of a plain var.<varName>.<path> reference.
+func attributePath(varName string, expr hclsyntax.Expression) ([]string, bool) {
+	traversal, ok := expr.(*hclsyntax.ScopeTraversalExpr)
+	if !ok || traversal.Traversal.RootName() != "var" || len(traversal.Traversal) < 2 {
+		return nil, false
+	}
+	if attr, isAttr := traversal.Traversal[1].(hcl.TraverseAttr); !isAttr || attr.Name != varName {
+		return nil, false
+	}
+
+	rest := traversal.Traversal[2:]
+	path := traversalPath(rest)
+	if len(path) != len(rest) {
+		// Indexes and splats select elements, not the attribute itself
+		return nil, false
+	}
+	return path, true
+}
@@ -0,0 +1,225 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/glueckkanja/marinatemd/internal/schema"
+)
+
+// Draft is the JSON Schema dialect of generated documents.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema. Only the keywords needed to describe
+// Terraform types are supported.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 any                `json:"type,omitempty"` // Type name or list of type names
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"` // false or *Schema
+	PrefixItems          []*Schema          `json:"prefixItems,omitempty"`
+	Items                any                `json:"items,omitempty"` // false or *Schema
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	UniqueItems          bool               `json:"uniqueItems,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Examples             []any              `json:"examples,omitempty"`
+}
+
+// Input is a module input variable for ForInputs.
+type Input struct {
+	Name     string // Variable name as used in tfvars files
+	Required bool   // The variable has no default
+	Schema   *schema.Schema
+}
+
+// Marshal encodes a JSON Schema document with two-space indentation and a trailing newline.
+func Marshal(s *Schema) ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON schema: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// FromSchema converts the schema of one variable into a JSON Schema document for its value.
+func FromSchema(s *schema.Schema) *Schema {
+	doc := variableSchema(s)
+	doc.Schema = Draft
+	doc.Title = s.Variable
+	return doc
+}
+
+// ForInputs combines the schemas of several variables into one JSON Schema document for the
+// module's inputs, as written in a *.tfvars.json file. Other properties are allowed, since a
+// module usually has more variables than the documented ones.
+func ForInputs(title string, inputs []Input) *Schema {
+	doc := &Schema{
+		Schema:     Draft,
+		Title:      title,
+		Type:       "object",
+		Properties: make(map[string]*Schema, len(inputs)),
+	}
+	for _, input := range inputs {
+		doc.Properties[input.Name] = variableSchema(input.Schema)
+		if input.Required {
+			doc.Required = append(doc.Required, input.Name)
+		}
+	}
+	sort.Strings(doc.Required)
+	return doc
+}
+
+// variableSchema returns the JSON Schema of a variable's value. Object variables are
+// described by their attributes, all other variables by the root-level _marinate block.
+// Variables accept null unless they are declared nullable = false.
+func variableSchema(s *schema.Schema) *Schema {
+	c := &converter{maskValues: s.MasksValues()}
+
+	var js *Schema
+	switch {
+	case s.Marinate != nil:
+		js = c.nodeSchema(s.Marinate, s.SchemaNodes)
+	case len(s.SchemaNodes) > 0:
+		js = c.objectSchema(s.SchemaNodes)
+	default:
+		js = &Schema{}
+	}
+	if s.Default != nil && !c.maskValues {
+		js.Default = schema.JSONValue(s.Default)
+	}
+	if s.IsNullable() {
+		allowNull(js)
+	}
+	return js
+}
+
+// allowNull makes a schema accept null, like Terraform does for nullable variables and
+// optional attributes. Schemas without a type accept it already.
+func allowNull(js *Schema) {
+	if typ, ok := js.Type.(string); ok && typ != "" {
+		js.Type = []string{typ, "null"}
+	}
+	if len(js.Enum) > 0 {
+		js.Enum = append(js.Enum, nil)
+	}
+}
+
+// converter converts the nodes of one variable.
+type converter struct {
+	maskValues bool // Leave out defaults and examples of sensitive variables
+}
+
+// nodeSchema returns the JSON Schema of a node, including its documentation and value rules.
+func (c *converter) nodeSchema(info *schema.MarinateInfo, attrs map[string]*schema.Node) *Schema {
+	if info == nil {
+		return &Schema{}
+	}
+
+	var js *Schema
+	switch info.Type {
+	case "list", "set":
+		js = &Schema{Type: "array", Items: c.elementSchema(info.ElementType, attrs), UniqueItems: info.Type == "set"}
+	case "map":
+		js = &Schema{Type: "object", AdditionalProperties: c.elementSchema(info.ValueType, attrs)}
+	default:
+		js = c.elementSchema(info.Type, attrs)
+	}
+
+	showDescription := info.ShowDescription == nil || *info.ShowDescription
+	if showDescription && !schema.IsTODO(info.Description) {
+		js.Description = strings.TrimSpace(info.Description)
+	}
+	for _, allowed := range info.AllowedValues {
+		js.Enum = append(js.Enum, schema.JSONValue(allowed))
+	}
+	js.Pattern = info.Pattern
+	js.Minimum = info.Min
+	js.Maximum = info.Max
+	if !c.maskValues {
+		js.Default = schema.JSONValue(info.Default)
+		if info.Example != nil {
+			js.Examples = []any{schema.JSONValue(info.Example)}
+		}
+	}
+
+	return js
+}
+
+// elementSchema returns the JSON Schema of a type kind. Object and tuple structure comes from
+// the child nodes; nested maps are described by their _values child.
+func (c *converter) elementSchema(kind string, attrs map[string]*schema.Node) *Schema {
+	switch kind {
+	case "string":
+		return &Schema{Type: "string"}
+	case "number":
+		return &Schema{Type: "number"}
+	case "bool":
+		return &Schema{Type: "boolean"}
+	case "object":
+		return c.objectSchema(attrs)
+	case "tuple":
+		return c.tupleSchema(attrs)
+	case "map":
+		if values := attrs["_values"]; values != nil {
+			return c.nodeSchema(values.Marinate, values.Attributes)
+		}
+		return &Schema{Type: "object"}
+	case "list", "set":
+		return &Schema{Type: "array"}
+	default:
+		return &Schema{}
+	}
+}
+
+// objectSchema returns the schema of an object with the given attribute nodes.
+// Attributes that are not declared in the type are rejected; optional attributes may be null.
+func (c *converter) objectSchema(attrs map[string]*schema.Node) *Schema {
+	js := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema, len(attrs)),
+		AdditionalProperties: false,
+	}
+	for _, name := range schema.SortedAttributeNames(attrs) {
+		node := attrs[name]
+		if node == nil {
+			continue
+		}
+		property := c.nodeSchema(node.Marinate, node.Attributes)
+		if node.Marinate != nil && node.Marinate.Required {
+			js.Required = append(js.Required, name)
+		} else {
+			allowNull(property)
+		}
+		js.Properties[name] = property
+	}
+	return js
+}
+
+// tupleSchema returns the schema of a tuple with the positional child nodes _0, _1, ...
+func (c *converter) tupleSchema(attrs map[string]*schema.Node) *Schema {
+	var elems []*Schema
+	for _, name := range schema.SortedAttributeNames(attrs) {
+		if _, ok := schema.TupleElementIndex(name); !ok {
+			continue
+		}
+		node := attrs[name]
+		if node == nil {
+			elems = append(elems, &Schema{})
+			continue
+		}
+		elems = append(elems, c.nodeSchema(node.Marinate, node.Attributes))
+	}
+
+	length := len(elems)
+	return &Schema{Type: "array", PrefixItems: elems, Items: false, MinItems: &length, MaxItems: &length}
+}
@@ -0,0 +1,208 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/glueckkanja/marinatemd/internal/hclparse"
+	"github.com/glueckkanja/marinatemd/internal/jsonschema"
+	"github.com/glueckkanja/marinatemd/internal/schema"
+)
+
+func buildSchema(t *testing.T, variable *hclparse.Variable) *schema.Schema {
+	t.Helper()
+
+	s, err := schema.NewBuilder().BuildFromVariable(variable)
+	if err != nil {
+		t.Fatalf("BuildFromVariable() error = %v", err)
+	}
+	return s
+}
+
+func marshal(t *testing.T, doc *jsonschema.Schema) string {
+	t.Helper()
+
+	data, err := jsonschema.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	return string(data)
+}
+
+func TestFromSchema(t *testing.T) {
+	t.Parallel()
+
+	s := buildSchema(t, &hclparse.Variable{
+		Name: "app",
+		Type: `object({
+			name  = string
+			port  = optional(number, 8080)
+			tags  = optional(set(string))
+			rules = optional(map(object({ priority = number })))
+			pair  = optional(tuple([string, bool]))
+		})`,
+		MarinatedID: "app",
+	})
+	s.SchemaNodes["name"].Marinate.Description = "Name of the app"
+	s.SchemaNodes["name"].Marinate.Pattern = "^[a-z]+$"
+	s.SchemaNodes["port"].Marinate.Example = 443
+	maxPort := 65535.0
+	s.SchemaNodes["port"].Marinate.Max = &maxPort
+	s.SchemaNodes["tags"].Marinate.AllowedValues = []any{"a", "b"}
+
+	want := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "app",
+  "type": "object",
+  "properties": {
+    "name": {
+      "description": "Name of the app",
+      "type": "string",
+      "pattern": "^[a-z]+$"
+    },
+    "pair": {
+      "type": [
+        "array",
+        "null"
+      ],
+      "prefixItems": [
+        {
+          "type": "string"
+        },
+        {
+          "type": "boolean"
+        }
+      ],
+      "items": false,
+      "minItems": 2,
+      "maxItems": 2
+    },
+    "port": {
+      "type": [
+        "number",
+        "null"
+      ],
+      "maximum": 65535,
+      "default": 8080,
+      "examples": [
+        443
+      ]
+    },
+    "rules": {
+      "type": [
+        "object",
+        "null"
+      ],
+      "additionalProperties": {
+        "type": "object",
+        "properties": {
+          "priority": {
+            "type": "number"
+          }
+        },
+        "required": [
+          "priority"
+        ],
+        "additionalProperties": false
+      }
+    },
+    "tags": {
+      "type": [
+        "array",
+        "null"
+      ],
+      "items": {
+        "type": "string"
+      },
+      "uniqueItems": true,
+      "enum": [
+        "a",
+        "b",
+        null
+      ]
+    }
+  },
+  "required": [
+    "name"
+  ],
+  "additionalProperties": false
+}
+`
+	if got := marshal(t, jsonschema.FromSchema(s)); got != want {
+		t.Errorf("FromSchema() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestForInputs tests that variables without a default are required, that nullable variables
+// and optional attributes accept null, and that defaults and examples of sensitive variables
+// are left out.
+func TestForInputs(t *testing.T) {
+	t.Parallel()
+
+	tags := buildSchema(t, &hclparse.Variable{
+		Name:        "tags",
+		Type:        "map(string)",
+		MarinatedID: "tags",
+		Default:     map[string]any{"env": "dev"},
+		Nullable:    true,
+	})
+	db := buildSchema(t, &hclparse.Variable{
+		Name:        "db",
+		Type:        `object({ password = optional(string, "changeme") })`,
+		MarinatedID: "db",
+		Sensitive:   true,
+	})
+	region := buildSchema(t, &hclparse.Variable{
+		Name:        "region",
+		Type:        "string",
+		MarinatedID: "region",
+	})
+
+	doc := jsonschema.ForInputs("module", []jsonschema.Input{
+		{Name: "tags", Required: false, Schema: tags},
+		{Name: "db", Required: true, Schema: db},
+		{Name: "region", Required: true, Schema: region},
+	})
+
+	want := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "module",
+  "type": "object",
+  "properties": {
+    "db": {
+      "type": "object",
+      "properties": {
+        "password": {
+          "type": [
+            "string",
+            "null"
+          ]
+        }
+      },
+      "additionalProperties": false
+    },
+    "region": {
+      "type": "string"
+    },
+    "tags": {
+      "type": [
+        "object",
+        "null"
+      ],
+      "additionalProperties": {
+        "type": "string"
+      },
+      "default": {
+        "env": "dev"
+      }
+    }
+  },
+  "required": [
+    "db",
+    "region"
+  ]
+}
+`
+	if got := marshal(t, doc); got != want {
+		t.Errorf("ForInputs() =\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,67 @@
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"strings"
+
+	"github.com/glueckkanja/marinatemd/internal/schema"
+)
+
+// RenderSummary applies the collapse summary template to a context and returns the text of
+// the <summary> element. The attribute and description are HTML-escaped.
+func (tc *TemplateConfig) RenderSummary(ctx SummaryContext) string {
+	if tc.compiledSummary == nil {
+		if err := tc.compileTemplate(); err != nil {
+			return html.EscapeString(ctx.Attribute)
+		}
+	}
+
+	ctx.Attribute = html.EscapeString(ctx.Attribute)
+	ctx.Description = html.EscapeString(ctx.Description)
+
+	var buf bytes.Buffer
+	if err := tc.compiledSummary.Execute(&buf, ctx); err != nil {
+		return ctx.Attribute
+	}
+	// The summary has to stay on the line of its tags
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+// collapses reports whether the attributes of a node are collapsed: count attributes at depth.
+func (tc *TemplateConfig) collapses(depth, count int) bool {
+	return (tc.CollapseDepth > 0 && depth >= tc.CollapseDepth) ||
+		(tc.CollapseThreshold > 0 && count >= tc.CollapseThreshold)
+}
+
+// renderCollapsedChildren renders the attributes of a node inside a <details> block. GitHub and
+// Azure DevOps only render markdown inside an HTML block when blank lines separate the two, so
+// the tags get blank lines around them. They are indented like the node's text to stay part of
+// its list item.
+func (r *Renderer) renderCollapsedChildren(
+	name string,
+	node *schema.Node,
+	depth int,
+	vars *variableContext,
+	builder *strings.Builder,
+) error {
+	ctx := SummaryContext{Attribute: name, Count: len(node.Attributes), Depth: depth + 1}
+	if node.Marinate != nil {
+		ctx.Type = node.Marinate.Type
+		if node.Marinate.ShowDescription == nil || *node.Marinate.ShowDescription {
+			ctx.Description = strings.TrimSpace(node.Marinate.Description)
+		}
+	}
+	indent := strings.Repeat(" ", len(r.templateCfg.FormatIndent(depth)))
+
+	ensureBlankLine(builder)
+	builder.WriteString(indent + "<details><summary>" + r.templateCfg.RenderSummary(ctx) + "</summary>\n\n")
+
+	if err := r.renderNodeChildren(node, depth, vars, builder); err != nil {
+		return err
+	}
+
+	ensureBlankLine(builder)
+	builder.WriteString(indent + "</details>\n\n")
+	return nil
+}
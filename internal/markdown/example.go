@@ -0,0 +1,54 @@
+package markdown
+
+import (
+	"strings"
+)
+
+// Example formats.
+const (
+	ExampleFormatModule = "module"
+	ExampleFormatTfvars = "tfvars"
+)
+
+// ExampleInput is a variable value in a generated example.
+type ExampleInput struct {
+	Name  string
+	Value any
+}
+
+// RenderTfvars renders inputs as a variable definitions file, one name = value per variable.
+func RenderTfvars(inputs []ExampleInput) string {
+	var builder strings.Builder
+	writeHCLArguments(&builder, inputs, "")
+	return builder.String()
+}
+
+// RenderModuleCall renders inputs as the arguments of a module block calling source.
+func RenderModuleCall(name, source string, inputs []ExampleInput) string {
+	var builder strings.Builder
+	builder.WriteString("module " + quoteHCL(name) + " {\n")
+	builder.WriteString(valueIndent + "source = " + quoteHCL(source) + "\n")
+	if len(inputs) > 0 {
+		builder.WriteString("\n")
+		writeHCLArguments(&builder, inputs, valueIndent)
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// writeHCLArguments writes one argument per input in the given order, with the equals signs of
+// consecutive single-line arguments aligned like terraform fmt. Values are written as multi-line
+// HCL literals.
+func writeHCLArguments(builder *strings.Builder, inputs []ExampleInput, indent string) {
+	names := make([]string, len(inputs))
+	values := make([]string, len(inputs))
+	for i, input := range inputs {
+		var value strings.Builder
+		encodeHCL(&value, input.Value, true, indent)
+		names[i], values[i] = input.Name, value.String()
+	}
+
+	for i, width := range alignedWidths(names, values) {
+		builder.WriteString(indent + names[i] + strings.Repeat(" ", width-len(names[i])) + " = " + values[i] + "\n")
+	}
+}
@@ -0,0 +1,43 @@
+package markdown_test
+
+import (
+	"testing"
+
+	"github.com/glueckkanja/marinatemd/internal/markdown"
+)
+
+func TestRenderModuleCall(t *testing.T) {
+	inputs := []markdown.ExampleInput{
+		{Name: "name", Value: "web"},
+		{Name: "app_config", Value: map[string]any{
+			"port": 8080,
+			"tags": []any{"a"},
+		}},
+		{Name: "location", Value: "westeurope"},
+		{Name: "sku", Value: "S1"},
+	}
+
+	// Only runs of consecutive single-line arguments are aligned, like terraform fmt
+	got := markdown.RenderModuleCall("app", "./modules/app", inputs)
+	want := `module "app" {
+  source = "./modules/app"
+
+  name = "web"
+  app_config = {
+    port = 8080
+    tags = [
+      "a",
+    ]
+  }
+  location = "westeurope"
+  sku      = "S1"
+}
+`
+	if got != want {
+		t.Errorf("RenderModuleCall() =\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := markdown.RenderTfvars(inputs[:1]); got != "name = \"web\"\n" {
+		t.Errorf("RenderTfvars() = %q", got)
+	}
+}
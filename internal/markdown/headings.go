@@ -0,0 +1,127 @@
+package markdown
+
+import (
+	"strings"
+
+	"github.com/glueckkanja/marinatemd/internal/schema"
+)
+
+// renderHeadings renders a schema in headings mode: the top-level attributes without nested
+// attributes as a list or table, the variable-level default and constraints, then a section
+// for every attribute with nested attributes. The variable-level lines come before the first
+// heading so they don't read as part of the last section.
+func (r *Renderer) renderHeadings(s *schema.Schema, vars *variableContext, constraints []string, builder *strings.Builder) {
+	leaves, sections := splitSections(s.SchemaNodes)
+	constraints = append(constraints, r.renderLeaves(leaves, "", vars, builder)...)
+	r.writeVariableFooter(s.Default, vars, constraints, builder)
+
+	var out strings.Builder
+	r.renderSections(s.Variable, s.SchemaNodes, sections, "", r.templateCfg.headingLevel(), vars, &out)
+	if out.Len() > 0 {
+		ensureBlankLine(builder)
+		writeAttributes(out.String(), builder)
+	}
+}
+
+// splitSections returns the attributes without nested attributes by name, and the sorted
+// names of the attributes with nested attributes, which get a section.
+func splitSections(nodes map[string]*schema.Node) (map[string]*schema.Node, []string) {
+	var sections []string
+	leaves := make(map[string]*schema.Node)
+	for _, name := range schema.SortedAttributeNames(nodes) {
+		switch node := nodes[name]; {
+		case node == nil:
+		case len(node.Attributes) > 0:
+			sections = append(sections, name)
+		default:
+			leaves[name] = node
+		}
+	}
+	return leaves, sections
+}
+
+// renderLeaves renders attributes without nested attributes as a list or table. Returns the
+// validations of table rows, which have no room for them.
+func (r *Renderer) renderLeaves(
+	leaves map[string]*schema.Node,
+	parentPath string,
+	vars *variableContext,
+	builder *strings.Builder,
+) []string {
+	if r.templateCfg.HeadingLeaves == RenderModeTable {
+		return r.renderTable(leaves, parentPath, vars, builder)
+	}
+	for _, name := range schema.SortedAttributeNames(leaves) {
+		r.renderNodeContent(name, leaves[name], 0, vars, builder)
+	}
+	return nil
+}
+
+// renderSections renders a section for each of the named nodes: a heading with an anchor, the
+// attribute itself, its leaves with their constraints, and its own sections one level deeper,
+// at most h6.
+func (r *Renderer) renderSections(
+	variable string,
+	nodes map[string]*schema.Node,
+	names []string,
+	parentPath string,
+	level int,
+	vars *variableContext,
+	builder *strings.Builder,
+) {
+	for _, name := range names {
+		node := nodes[name]
+		path := tablePath(parentPath, name)
+
+		ensureBlankLine(builder)
+		builder.WriteString(`<a name="` + headingAnchor(variable, path) + `"></a>` + "\n")
+		builder.WriteString(strings.Repeat("#", min(level, maxHeadingLevel)) + " " + r.templateCfg.escape(path) + "\n\n")
+		r.renderSectionIntro(name, path, node.Marinate, vars, builder)
+
+		elements := elementPath(path, node.Marinate)
+		leaves, sections := splitSections(node.Attributes)
+		if constraints := r.renderLeaves(leaves, elements, vars, builder); len(constraints) > 0 {
+			ensureBlankLine(builder)
+			writeConstraintList(constraints, builder)
+		}
+		r.renderSections(variable, node.Attributes, sections, elements, level+1, vars, builder)
+	}
+}
+
+// renderSectionIntro renders the attribute line, effective default and validations of a
+// section attribute as paragraphs below its heading.
+func (r *Renderer) renderSectionIntro(
+	name, path string,
+	info *schema.MarinateInfo,
+	vars *variableContext,
+	builder *strings.Builder,
+) {
+	if info == nil {
+		return
+	}
+
+	ctx := r.newTemplateContext(name, info, vars, "")
+	ctx.Path = path
+	builder.WriteString(r.renderAttributeLine(ctx))
+	builder.WriteString("\n\n")
+
+	if ctx.HasEffectiveDefault && (!ctx.HasDefault || ctx.EffectiveDefault != ctx.Default) {
+		r.writeValueLine("", "Effective default", ctx.EffectiveDefault, builder)
+		builder.WriteString("\n")
+	}
+
+	for _, constraint := range info.Constraints {
+		builder.WriteString("Constraint: ")
+		builder.WriteString(formatConstraint(constraint))
+		builder.WriteString("\n\n")
+	}
+}
+
+// headingAnchor returns the anchor of a section, e.g. "app-database-tls" for the path
+// database.tls of the variable app. Characters other than letters, digits, - and _ separate words.
+func headingAnchor(variable, path string) string {
+	words := strings.FieldsFunc(strings.ToLower(variable+"."+path), func(c rune) bool {
+		return (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '_'
+	})
+	return strings.Join(words, "-")
+}
@@ -347,3 +347,97 @@ Default: "default"`
 		}
 	}
 }
+
+func TestInjector_InjectIntoFile_ReplacesContentWithDefaultLines(t *testing.T) {
+	originalContent := "Description: <!-- MARINATED: app_config -->\n\n" +
+		"- `name` - (Required) Name\n\nDefault: `{ name = \"old\" }`\n\n" +
+		"<!-- /MARINATED: app_config -->\n\nType: object\n"
+
+	tmpFile := filepath.Join(t.TempDir(), "README.md")
+	if err := os.WriteFile(tmpFile, []byte(originalContent), 0644); err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+
+	// The rendered content's own "Default:" line must not be mistaken for terraform-docs output
+	injector := markdown.NewInjector()
+	for range 2 {
+		if err := injector.InjectIntoFile(tmpFile, "app_config", "- `name` - (Required) Name\n\nDefault: `{ name = \"new\" }`"); err != nil {
+			t.Fatalf("InjectIntoFile() failed: %v", err)
+		}
+	}
+
+	resultContent, err := os.ReadFile(tmpFile)
+	if err != nil {
+		t.Fatalf("Failed to read result file: %v", err)
+	}
+
+	want := "Description: <!-- MARINATED: app_config -->\n\n" +
+		"- `name` - (Required) Name\n\nDefault: `{ name = \"new\" }`\n\n" +
+		"<!-- /MARINATED: app_config -->\n\nType: object\n"
+	if string(resultContent) != want {
+		t.Errorf("Expected:\n%s\nGot:\n%s", want, resultContent)
+	}
+}
+
+func TestInjector_Inject(t *testing.T) {
+	content := "# Docs\n\n<!-- MARINATED: app_config -->\n\nold content\n\n<!-- /MARINATED: app_config -->\n\n## Next\n"
+
+	injector := markdown.NewInjector()
+	result, err := injector.Inject(content, "app_config", "- `name` - (Required) The name")
+	if err != nil {
+		t.Fatalf("Inject() error = %v", err)
+	}
+
+	want := "# Docs\n\n<!-- MARINATED: app_config -->\n\n- `name` - (Required) The name\n\n<!-- /MARINATED: app_config -->\n\n## Next\n"
+	if result != want {
+		t.Errorf("Inject() =\n%q\nwant:\n%q", result, want)
+	}
+
+	// Injecting the same content again must not change anything
+	again, err := injector.Inject(result, "app_config", "- `name` - (Required) The name")
+	if err != nil {
+		t.Fatalf("Inject() error = %v", err)
+	}
+	if again != result {
+		t.Errorf("Inject() is not idempotent:\n%q", again)
+	}
+
+	if _, err := injector.Inject(content, "missing", "text"); err == nil {
+		t.Error("Inject() expected error for missing marker")
+	}
+}
+
+// TestInjector_InjectExample tests that example markers are found and injected
+// independently of the variable markers.
+func TestInjector_InjectExample(t *testing.T) {
+	content := "<!-- MARINATED: app -->\n<!-- /MARINATED: app -->\n\n## Usage\n\n" +
+		"<!-- MARINATED-EXAMPLE: minimal -->\n<!-- /MARINATED-EXAMPLE: minimal -->\n"
+
+	filePath := filepath.Join(t.TempDir(), "README.md")
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	injector := markdown.NewInjector()
+	variables, err := injector.FindMarkers(filePath)
+	if err != nil {
+		t.Fatalf("FindMarkers() error = %v", err)
+	}
+	examples, err := injector.FindExampleMarkers(filePath)
+	if err != nil {
+		t.Fatalf("FindExampleMarkers() error = %v", err)
+	}
+	if strings.Join(variables, ",") != "app" || strings.Join(examples, ",") != "minimal" {
+		t.Errorf("markers = %v and %v, want [app] and [minimal]", variables, examples)
+	}
+
+	result, err := injector.InjectExample(content, "minimal", "```hcl\nmodule \"app\" {}\n```")
+	if err != nil {
+		t.Fatalf("InjectExample() error = %v", err)
+	}
+	want := "<!-- MARINATED: app -->\n<!-- /MARINATED: app -->\n\n## Usage\n\n" +
+		"<!-- MARINATED-EXAMPLE: minimal -->\n\n```hcl\nmodule \"app\" {}\n```\n\n<!-- /MARINATED-EXAMPLE: minimal -->\n"
+	if result != want {
+		t.Errorf("InjectExample() =\n%q\nwant:\n%q", result, want)
+	}
+}
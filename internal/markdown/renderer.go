@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"os"
 	"slices"
-	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/glueckkanja/marinatemd/internal/schema"
@@ -38,6 +38,18 @@ func NewRendererWithTemplate(templateCfg *TemplateConfig) *Renderer {
 	}
 }
 
+// NewTerraformRenderer creates a renderer for variable descriptions in Terraform files. Headings
+// cannot be nested in a description, so the headings render mode falls back to the list mode.
+func NewTerraformRenderer(templateCfg *TemplateConfig) *Renderer {
+	r := NewRendererWithTemplate(templateCfg)
+	if r.templateCfg.RenderMode == RenderModeHeadings {
+		listCfg := *r.templateCfg
+		listCfg.RenderMode = RenderModeList
+		r.templateCfg = &listCfg
+	}
+	return r
+}
+
 // RenderSchema converts a schema to hierarchical markdown documentation.
 func (r *Renderer) RenderSchema(s *schema.Schema) (string, error) {
 	if s == nil {
@@ -45,137 +57,439 @@ func (r *Renderer) RenderSchema(s *schema.Schema) (string, error) {
 	}
 
 	var builder strings.Builder
+	vars := newVariableContext(s)
 
-	// Render each top-level node in sorted order for deterministic output
-	nodeNames := make([]string, 0, len(s.SchemaNodes))
-	for name := range s.SchemaNodes {
-		nodeNames = append(nodeNames, name)
+	// Variables other than objects start with a description of the variable itself
+	if s.Marinate != nil {
+		r.renderRootLeadIn(s.Marinate, len(s.SchemaNodes) > 0, &builder)
+	}
+
+	constraints := make([]string, 0, len(s.Constraints))
+	for _, constraint := range s.Constraints {
+		constraints = append(constraints, formatConstraint(constraint))
+	}
+
+	switch r.templateCfg.RenderMode {
+	case RenderModeTable:
+		// Table rows have no room for attribute validations; they join the variable's constraints
+		constraints = append(constraints, r.renderTable(s.SchemaNodes, "", vars, &builder)...)
+	case RenderModeHeadings:
+		r.renderHeadings(s, vars, constraints, &builder)
+		return builder.String(), nil
+	default:
+		var list strings.Builder
+		if err := r.renderList(s.SchemaNodes, vars, &list); err != nil {
+			return "", err
+		}
+		writeAttributes(list.String(), &builder)
+	}
+
+	r.writeVariableFooter(s.Default, vars, constraints, &builder)
+	return builder.String(), nil
+}
+
+// writeVariableFooter writes the variable-level default and the validations of the whole
+// variable, which close the documentation of the attributes.
+func (r *Renderer) writeVariableFooter(
+	defaultValue any,
+	vars *variableContext,
+	constraints []string,
+	builder *strings.Builder,
+) {
+	if defaultStr, hasDefault := r.formatValue(defaultValue, vars, ""); hasDefault {
+		if builder.Len() > 0 {
+			builder.WriteString("\n")
+		}
+		r.writeValueLine("", "Default", defaultStr, builder)
+	}
+
+	if len(constraints) > 0 {
+		if builder.Len() > 0 {
+			builder.WriteString("\n")
+		}
+		writeConstraintList(constraints, builder)
 	}
-	sort.Strings(nodeNames)
+}
+
+// writeConstraintList writes formatted constraints as a list below a "Constraints:" label.
+func writeConstraintList(constraints []string, builder *strings.Builder) {
+	builder.WriteString("Constraints:\n\n")
+	for _, constraint := range constraints {
+		builder.WriteString("- ")
+		builder.WriteString(constraint)
+		builder.WriteString("\n")
+	}
+}
+
+// renderList renders the top-level nodes and their attributes as a nested list.
+func (r *Renderer) renderList(nodes map[string]*schema.Node, vars *variableContext, builder *strings.Builder) error {
+	// Render each top-level node in sorted order for deterministic output
+	nodeNames := schema.SortedAttributeNames(nodes)
 
 	for i, nodeName := range nodeNames {
-		node := s.SchemaNodes[nodeName]
+		node := nodes[nodeName]
 
 		// Insert separator before top-level object nodes if configured for depth 0
 		if r.shouldInsertSeparator(i, node, 0) {
 			indent := r.templateCfg.FormatIndent(0)
-			r.insertSeparator(indent, &builder)
+			r.insertSeparator(indent, builder)
 		}
 
-		if err := r.renderNode(nodeName, node, 0, &builder); err != nil {
-			return "", fmt.Errorf("failed to render node %s: %w", nodeName, err)
+		if err := r.renderNode(nodeName, node, 0, vars, builder); err != nil {
+			return fmt.Errorf("failed to render node %s: %w", nodeName, err)
 		}
 	}
 
-	return builder.String(), nil
+	return nil
+}
+
+// formatConstraint renders a validation as its error message followed by the condition,
+// e.g. "Port must be positive. (`var.app.port > 0`)". Whitespace is collapsed to keep it on one line.
+func formatConstraint(c *schema.Constraint) string {
+	condition := strings.Join(strings.Fields(c.Condition), " ")
+	condition = "`" + strings.NewReplacer("( ", "(", " )", ")").Replace(condition) + "`"
+	message := strings.Join(strings.Fields(c.ErrorMessage), " ")
+	if message == "" {
+		return condition
+	}
+	return message + " (" + condition + ")"
+}
+
+// variableContext holds the variable-level settings that apply to every attribute of a schema.
+type variableContext struct {
+	sensitive  bool
+	nullable   bool
+	ephemeral  bool
+	maskValues bool // Replace defaults and examples with the sensitive mask
+}
+
+func newVariableContext(s *schema.Schema) *variableContext {
+	return &variableContext{
+		sensitive:  s.Sensitive,
+		nullable:   s.IsNullable(),
+		ephemeral:  s.Ephemeral,
+		maskValues: s.MasksValues(),
+	}
+}
+
+// renderRootLeadIn renders the root-level description of a variable followed by a lead-in
+// line such as "Map of objects keyed by rule name, each with:", or for a simple type a type
+// line such as "Type: `string`".
+func (r *Renderer) renderRootLeadIn(info *schema.MarinateInfo, hasChildren bool, builder *strings.Builder) {
+	showDescription := info.ShowDescription == nil || *info.ShowDescription
+	if showDescription && info.Description != "" && !schema.IsTODO(info.Description) {
+		builder.WriteString(strings.TrimSpace(info.Description))
+		builder.WriteString("\n\n")
+	}
+
+	leadIn := collectionSummary(info)
+	if leadIn == "" {
+		if info.Type != "" && !hasChildren {
+			r.writeValueLine("", "Type", info.Type, builder)
+			builder.WriteString("\n")
+		}
+		return
+	}
+
+	switch {
+	case hasChildren && info.Type == "tuple":
+		leadIn += " with elements:"
+	case hasChildren:
+		leadIn += ", each with:"
+	default:
+		leadIn += "."
+	}
+
+	builder.WriteString(leadIn)
+	builder.WriteString("\n\n")
+}
+
+// collectionSummary describes a collection type in words, e.g. "List of strings"
+// or "Map of objects keyed by rule name". Returns an empty string for non-collection types.
+func collectionSummary(info *schema.MarinateInfo) string {
+	switch info.Type {
+	case "list", "set":
+		return fmt.Sprintf("%s of %s", capitalize(info.Type), pluralizeType(info.ElementType))
+	case "map":
+		summary := "Map of " + pluralizeType(info.ValueType)
+		if info.KeyDescription != "" {
+			summary += " keyed by " + info.KeyDescription
+		}
+		return summary
+	case "tuple":
+		return "Tuple"
+	default:
+		return ""
+	}
+}
+
+// pluralizeType returns the plural noun for a type name (e.g. "object" -> "objects").
+func pluralizeType(typeName string) string {
+	switch typeName {
+	case "":
+		return "values"
+	case "any":
+		return "any values"
+	default:
+		return typeName + "s"
+	}
+}
+
+// capitalize upper-cases the first letter of s.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
 }
 
 // renderNode recursively renders a node and its children.
-func (r *Renderer) renderNode(name string, node *schema.Node, depth int, builder *strings.Builder) error {
+func (r *Renderer) renderNode(
+	name string,
+	node *schema.Node,
+	depth int,
+	vars *variableContext,
+	builder *strings.Builder,
+) error {
 	if node == nil {
 		return nil
 	}
 
-	r.renderNodeContent(name, node, depth, builder)
+	r.renderNodeContent(name, node, depth, vars, builder)
 
 	if len(node.Attributes) > 0 {
-		return r.renderNodeChildren(node, depth, builder)
+		if r.templateCfg.collapses(depth+1, len(node.Attributes)) {
+			return r.renderCollapsedChildren(name, node, depth, vars, builder)
+		}
+		return r.renderNodeChildren(node, depth, vars, builder)
 	}
 
 	return nil
 }
 
 // renderNodeContent renders the current node's content if it has documentation.
-func (r *Renderer) renderNodeContent(name string, node *schema.Node, depth int, builder *strings.Builder) {
+func (r *Renderer) renderNodeContent(
+	name string,
+	node *schema.Node,
+	depth int,
+	vars *variableContext,
+	builder *strings.Builder,
+) {
 	if node.Marinate == nil {
 		return
 	}
 
-	// Determine the description to use
-	description := node.Marinate.Description
+	// Long default and example values continue below the attribute line
+	indent := r.templateCfg.FormatIndent(depth)
+	continuation := strings.Repeat(" ", len(indent))
+	ctx := r.newTemplateContext(name, node.Marinate, vars, continuation)
 
-	// Check ShowDescription field - if nil (omitted), default to true
-	// If explicitly set to false, use empty description (but still render the attribute)
-	showDescription := true // Default behavior
-	if node.Marinate.ShowDescription != nil {
-		showDescription = *node.Marinate.ShowDescription
+	// Skip rendering if there's no description and no metadata to show
+	if ctx.Description == "" && !ctx.HasType && !ctx.IsRequired && len(node.Attributes) == 0 {
+		return
 	}
 
-	if !showDescription {
-		description = "" // Clear description but continue rendering
+	builder.WriteString(indent)
+	builder.WriteString(r.renderAttributeLine(ctx))
+	builder.WriteString("\n")
+
+	childIndent := r.templateCfg.FormatIndent(depth + 1)
+
+	// Show what the attribute gets when the variable is omitted, unless that's its own default
+	if ctx.HasEffectiveDefault && (!ctx.HasDefault || ctx.EffectiveDefault != ctx.Default) {
+		effective, _ := r.formatValue(node.Marinate.EffectiveDefault, vars, strings.Repeat(" ", len(childIndent)))
+		r.writeValueLine(childIndent, "Effective default", effective, builder)
 	}
 
-	// Skip rendering if there's no description and no metadata to show
-	if description == "" && node.Marinate.Type == "" && !node.Marinate.Required {
-		// If there's truly nothing to render, skip it
-		if len(node.Attributes) == 0 {
-			return
-		}
+	// Validations that only reference this attribute are listed right below it
+	for _, constraint := range node.Marinate.Constraints {
+		builder.WriteString(childIndent)
+		builder.WriteString("Constraint: ")
+		builder.WriteString(formatConstraint(constraint))
+		builder.WriteString("\n")
 	}
+}
 
-	// Format default and example values for display
-	defaultStr := ""
-	hasDefault := false
-	if node.Marinate.Default != nil {
-		defaultStr = fmt.Sprintf("%v", node.Marinate.Default)
-		// If the default is an empty string, show it as "" to make it explicit
-		if defaultStr == "" {
-			defaultStr = `""`
-		}
-		// Replace map[] with {} for better readability (empty map/object)
-		if defaultStr == "map[]" {
-			defaultStr = "{}"
-		}
-		hasDefault = true // Default is present, even if it's an empty string
+// renderAttributeLine applies the attribute template and appends the review badge if needed.
+func (r *Renderer) renderAttributeLine(ctx TemplateContext) string {
+	rendered := r.templateCfg.RenderAttribute(ctx)
+	// Trim trailing whitespace of every line, including what a code block at the end leaves behind
+	rendered = trimTrailingSpace(rendered)
+	if ctx.NeedsReview && r.templateCfg.ReviewBadge != "" {
+		rendered += " " + r.templateCfg.ReviewBadge
 	}
+	return rendered
+}
 
-	exampleStr := ""
-	hasExample := false
-	if node.Marinate.Example != nil {
-		exampleStr = fmt.Sprintf("%v", node.Marinate.Example)
-		// If the example is an empty string, show it as "" to make it explicit
-		if exampleStr == "" {
-			exampleStr = `""`
-		}
-		// Replace map[] with {} for better readability (empty map/object)
-		if exampleStr == "map[]" {
-			exampleStr = "{}"
-		}
-		hasExample = true // Example is present, even if it's an empty string
+// newTemplateContext builds the template context of an attribute. Values are formatted with
+// formatValue; continuation indents the lines of code blocks.
+func (r *Renderer) newTemplateContext(
+	name string,
+	info *schema.MarinateInfo,
+	vars *variableContext,
+	continuation string,
+) TemplateContext {
+	// Check ShowDescription field - if nil (omitted), default to true
+	// If explicitly set to false, use empty description (but still render the attribute)
+	showDescription := info.ShowDescription == nil || *info.ShowDescription
+	description := info.Description
+	if !showDescription {
+		description = ""
 	}
 
+	defaultStr, hasDefault := r.formatValue(info.Default, vars, continuation)
+	exampleStr, hasExample := r.formatValue(info.Example, vars, continuation)
+	effectiveStr, hasEffective := r.formatValue(info.EffectiveDefault, vars, continuation)
+
 	// Determine required/optional text
 	requiredText := r.templateCfg.OptionalText
-	isRequired := node.Marinate.Required
-	if isRequired {
+	if info.Required {
 		requiredText = r.templateCfg.RequiredText
 	}
 
 	ctx := TemplateContext{
-		Attribute:       name,
-		Required:        requiredText,
-		IsRequired:      isRequired,
-		Description:     description,
-		ShowDescription: showDescription,
-		Type:            node.Marinate.Type,
-		Default:         defaultStr,
-		Example:         exampleStr,
-		HasDefault:      hasDefault,
-		HasExample:      hasExample,
-		HasType:         node.Marinate.Type != "",
+		Attribute:           name,
+		Path:                name,
+		Required:            requiredText,
+		IsRequired:          info.Required,
+		Description:         description,
+		ShowDescription:     showDescription,
+		Type:                info.Type,
+		Default:             defaultStr,
+		Example:             exampleStr,
+		HasDefault:          hasDefault,
+		HasExample:          hasExample,
+		HasType:             info.Type != "",
+		EffectiveDefault:    effectiveStr,
+		HasEffectiveDefault: hasEffective,
+		NeedsReview:         info.NeedsReview,
+		Sensitive:           vars.sensitive,
+		Nullable:            vars.nullable,
+		Ephemeral:           vars.ephemeral,
 	}
+	r.setValueRules(&ctx, info)
+	return ctx
+}
 
-	indent := r.templateCfg.FormatIndent(depth)
-	rendered := r.templateCfg.RenderAttribute(ctx)
-	// Trim trailing whitespace from the rendered line
-	rendered = strings.TrimRight(rendered, " \t")
+// formatValue formats a default or example value as a literal in the configured value format
+// and reports whether it is present. Lists and objects longer than the code block threshold
+// become a fenced code block on the following lines, indented by continuation so it stays
+// part of the list item. Values of sensitive variables are replaced by the sensitive mask
+// so they don't leak into documentation.
+func (r *Renderer) formatValue(value any, vars *variableContext, continuation string) (string, bool) {
+	if value == nil {
+		return "", false
+	}
+	if vars.maskValues {
+		if r.templateCfg.SensitiveMask == "" {
+			return DefaultSensitiveMask, true
+		}
+		return r.templateCfg.SensitiveMask, true
+	}
+
+	format := r.templateCfg.ValueFormat
+	formatted, err := EncodeValue(value, format, false)
+	if err != nil {
+		// Validate rejects unknown formats; fall back to HCL for configs that skipped it
+		format = ValueFormatHCL
+		formatted, _ = EncodeValue(value, format, false)
+	}
+
+	// Table cells hold a single line, so values never become code blocks there
+	threshold := r.templateCfg.CodeBlockThreshold
+	if threshold == 0 || len(formatted) <= threshold || !isComposite(value) || r.templateCfg.usesTables() {
+		return formatted, true
+	}
+
+	multiline, _ := EncodeValue(value, format, true)
+	if format == "" {
+		format = ValueFormatHCL
+	}
+
+	var block strings.Builder
+	block.WriteString("\n" + continuation + "```" + format + "\n")
+	for line := range strings.SplitSeq(multiline, "\n") {
+		block.WriteString(continuation + line + "\n")
+	}
+	// Text after the value continues as a paragraph of the same list item
+	block.WriteString(continuation + "```\n" + continuation)
+	return block.String(), true
+}
+
+// trimTrailingSpace removes trailing whitespace from every line and trailing empty lines.
+func trimTrailingSpace(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// writeAttributes writes rendered attributes without trailing blank lines, which sections and
+// collapsed attributes leave behind; what follows the attributes adds its own.
+func writeAttributes(content string, builder *strings.Builder) {
+	if content == "" {
+		return
+	}
+	builder.WriteString(strings.TrimRight(content, "\n"))
+	builder.WriteString("\n")
+}
+
+// ensureBlankLine ends the content with an empty line, so the block that follows starts on its own.
+func ensureBlankLine(builder *strings.Builder) {
+	content := builder.String()
+	switch {
+	case content == "", strings.HasSuffix(content, "\n\n"):
+	case strings.HasSuffix(content, "\n"):
+		builder.WriteString("\n")
+	default:
+		builder.WriteString("\n\n")
+	}
+}
+
+// writeValueLine writes a labelled value such as "Default: `{}`" on its own line.
+func (r *Renderer) writeValueLine(indent, label, value string, builder *strings.Builder) {
 	builder.WriteString(indent)
-	builder.WriteString(rendered)
+	builder.WriteString(trimTrailingSpace(label + ": " + codeSpan(value)))
 	builder.WriteString("\n")
 }
 
+// setValueRules formats the allowed values, pattern and range of a node for templates.
+// Allowed values are literals in the configured value format, like defaults.
+func (r *Renderer) setValueRules(ctx *TemplateContext, info *schema.MarinateInfo) {
+	if len(info.AllowedValues) > 0 {
+		values := make([]string, len(info.AllowedValues))
+		for i, value := range info.AllowedValues {
+			formatted, err := EncodeValue(value, r.templateCfg.ValueFormat, false)
+			if err != nil {
+				formatted, _ = EncodeValue(value, ValueFormatHCL, false)
+			}
+			values[i] = codeSpan(formatted)
+		}
+		ctx.AllowedValues = strings.Join(values, ", ")
+		ctx.HasAllowedValues = true
+	}
+	if info.Pattern != "" {
+		ctx.Pattern = info.Pattern
+		ctx.HasPattern = true
+	}
+	if info.Min != nil {
+		ctx.Min = strconv.FormatFloat(*info.Min, 'f', -1, 64)
+		ctx.HasMin = true
+	}
+	if info.Max != nil {
+		ctx.Max = strconv.FormatFloat(*info.Max, 'f', -1, 64)
+		ctx.HasMax = true
+	}
+}
+
 // renderNodeChildren renders all child attributes of a node.
-func (r *Renderer) renderNodeChildren(node *schema.Node, depth int, builder *strings.Builder) error {
+func (r *Renderer) renderNodeChildren(
+	node *schema.Node,
+	depth int,
+	vars *variableContext,
+	builder *strings.Builder,
+) error {
 	attrNames := r.getSortedAttributeNames(node)
 	childDepth := depth + 1
 
@@ -188,7 +502,7 @@ func (r *Renderer) renderNodeChildren(node *schema.Node, depth int, builder *str
 			r.insertSeparator(indent, builder)
 		}
 
-		if err := r.renderNode(attrName, attr, childDepth, builder); err != nil {
+		if err := r.renderNode(attrName, attr, childDepth, vars, builder); err != nil {
 			return err
 		}
 	}
@@ -197,13 +511,9 @@ func (r *Renderer) renderNodeChildren(node *schema.Node, depth int, builder *str
 }
 
 // getSortedAttributeNames returns a sorted list of attribute names for deterministic output.
+// Tuple positions are returned in positional order.
 func (r *Renderer) getSortedAttributeNames(node *schema.Node) []string {
-	attrNames := make([]string, 0, len(node.Attributes))
-	for attrName := range node.Attributes {
-		attrNames = append(attrNames, attrName)
-	}
-	sort.Strings(attrNames)
-	return attrNames
+	return schema.SortedAttributeNames(node.Attributes)
 }
 
 // shouldInsertSeparator determines if a separator should be inserted before this attribute.
@@ -244,15 +554,44 @@ func (i *Injector) InjectIntoFile(filePath string, variableName string, markdown
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Convert to string for easier manipulation
-	fileContent := string(content)
+	result, err := i.Inject(string(content), variableName, markdownContent)
+	if err != nil {
+		return err
+	}
+
+	// Write the modified content back to the file
+	if writeErr := os.WriteFile(filePath, []byte(result), 0600); writeErr != nil {
+		return fmt.Errorf("failed to write file: %w", writeErr)
+	}
 
+	return nil
+}
+
+// Inject replaces content at the MARINATED markers for variableName in fileContent
+// and returns the result. It is the in-memory counterpart of InjectIntoFile.
+func (i *Injector) Inject(fileContent string, variableName string, markdownContent string) (string, error) {
+	return i.inject(fileContent, markerVariable, variableName, markdownContent)
+}
+
+// InjectExample replaces content at the <!-- MARINATED-EXAMPLE: name --> markers in fileContent
+// and returns the result.
+func (i *Injector) InjectExample(fileContent string, name string, content string) (string, error) {
+	return i.inject(fileContent, markerExample, name, content)
+}
+
+// Marker kinds: <!-- MARINATED: id --> for variables, <!-- MARINATED-EXAMPLE: id --> for examples.
+const (
+	markerVariable = "MARINATED"
+	markerExample  = "MARINATED-EXAMPLE"
+)
+
+func (i *Injector) inject(fileContent, kind, id, content string) (string, error) {
 	// Build the markers to find - try both with escaped and unescaped underscores
-	startMarker := fmt.Sprintf("<!-- MARINATED: %s -->", variableName)
-	endMarker := fmt.Sprintf("<!-- /MARINATED: %s -->", variableName)
+	startMarker := fmt.Sprintf("<!-- %s: %s -->", kind, id)
+	endMarker := fmt.Sprintf("<!-- /%s: %s -->", kind, id)
 
-	escapedStartMarker := fmt.Sprintf("<!-- MARINATED: %s -->", strings.ReplaceAll(variableName, "_", "\\_"))
-	escapedEndMarker := fmt.Sprintf("<!-- /MARINATED: %s -->", strings.ReplaceAll(variableName, "_", "\\_"))
+	escapedStartMarker := fmt.Sprintf("<!-- %s: %s -->", kind, strings.ReplaceAll(id, "_", "\\_"))
+	escapedEndMarker := fmt.Sprintf("<!-- /%s: %s -->", kind, strings.ReplaceAll(id, "_", "\\_"))
 
 	// Check if either marker exists and determine which version we're using
 	foundStartMarker := startMarker
@@ -263,7 +602,7 @@ func (i *Injector) InjectIntoFile(filePath string, variableName string, markdown
 			foundStartMarker = escapedStartMarker
 			foundEndMarker = escapedEndMarker
 		} else {
-			return fmt.Errorf("marker %s not found in file", startMarker)
+			return "", fmt.Errorf("marker %s not found in file", startMarker)
 		}
 	}
 
@@ -280,7 +619,7 @@ func (i *Injector) InjectIntoFile(filePath string, variableName string, markdown
 		case strings.Contains(line, foundStartMarker):
 			foundBlock = true
 			inMarinatedBlock = true
-			i = writeMarinatedBlock(line, foundStartMarker, foundEndMarker, markdownContent, lines, i, &result)
+			i = writeMarinatedBlock(line, foundStartMarker, foundEndMarker, content, lines, i, &result)
 		case strings.Contains(line, foundEndMarker) && !inMarinatedBlock:
 			// Skip orphaned end markers
 			continue
@@ -298,15 +637,10 @@ func (i *Injector) InjectIntoFile(filePath string, variableName string, markdown
 	}
 
 	if !foundBlock {
-		return fmt.Errorf("marker %s not found in file", startMarker)
-	}
-
-	// Write the modified content back to the file
-	if writeErr := os.WriteFile(filePath, []byte(result.String()), 0600); writeErr != nil {
-		return fmt.Errorf("failed to write file: %w", writeErr)
+		return "", fmt.Errorf("marker %s not found in file", startMarker)
 	}
 
-	return nil
+	return result.String(), nil
 }
 
 func writeMarinatedBlock(
@@ -331,7 +665,15 @@ func writeMarinatedBlock(
 	result.WriteString(foundEndMarker)
 	result.WriteString("\n")
 
-	// Skip everything until we find the end marker or a significant section
+	// Skip the previous content up to its end marker. The content itself may contain
+	// lines like "Default:", so the end marker is looked up before anything else.
+	for end := idx + 1; end < len(lines) && !strings.Contains(lines[end], foundStartMarker); end++ {
+		if strings.Contains(lines[end], foundEndMarker) {
+			return end
+		}
+	}
+
+	// Without an end marker, skip everything until the next significant section
 	idx++
 	for idx < len(lines) {
 		currentLine := lines[idx]
@@ -359,20 +701,30 @@ func writeMarinatedBlock(
 // FindMarkers scans a file and returns all MARINATED markers found.
 // Returns a slice of variable names extracted from <!-- MARINATED: name --> markers.
 func (i *Injector) FindMarkers(filePath string) ([]string, error) {
+	return i.findMarkers(filePath, markerVariable)
+}
+
+// FindExampleMarkers scans a file and returns the names of all <!-- MARINATED-EXAMPLE: name --> markers.
+func (i *Injector) FindExampleMarkers(filePath string) ([]string, error) {
+	return i.findMarkers(filePath, markerExample)
+}
+
+func (i *Injector) findMarkers(filePath, kind string) ([]string, error) {
 	// Read the file
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Find all MARINATED markers using a simple string search
+	// Find all markers of this kind using a simple string search
 	var markers []string
+	startTag := "<!-- " + kind + ":"
 
 	for line := range strings.SplitSeq(string(content), "\n") {
 		// Look for <!-- MARINATED: variable_name -->
-		if strings.Contains(line, "<!-- MARINATED:") {
+		if strings.Contains(line, startTag) {
 			// Extract the variable name
-			before, after, found := strings.Cut(line, "<!-- MARINATED:")
+			before, after, found := strings.Cut(line, startTag)
 			if !found {
 				continue
 			}
@@ -337,6 +337,557 @@ func TestRenderSchema_ShowDescriptionExplicitlyFalse(t *testing.T) {
 	}
 }
 
+func TestRenderSchema_ReviewBadge(t *testing.T) {
+	s := &schema.Schema{
+		Variable: "test_var",
+		Version:  "1",
+		SchemaNodes: map[string]*schema.Node{
+			"changed": {
+				Marinate:   &schema.MarinateInfo{Description: "Written for a string", NeedsReview: true},
+				Attributes: map[string]*schema.Node{},
+			},
+			"unchanged": {
+				Marinate:   &schema.MarinateInfo{Description: "Still correct"},
+				Attributes: map[string]*schema.Node{},
+			},
+		},
+	}
+
+	// Without a configured badge nothing changes
+	result, err := NewRenderer().RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(result, "review") {
+		t.Errorf("Expected no badge by default, got:\n%s", result)
+	}
+
+	cfg := DefaultTemplateConfig()
+	cfg.ReviewBadge = "*(needs review)*"
+	result, err = NewRendererWithTemplate(cfg).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := "- `changed` - (Optional) Written for a string *(needs review)*\n" +
+		"- `unchanged` - (Optional) Still correct\n"
+	if result != want {
+		t.Errorf("Expected:\n%s\nGot:\n%s", want, result)
+	}
+}
+
+func TestRenderSchema_Constraints(t *testing.T) {
+	s := &schema.Schema{
+		Variable: "app",
+		Version:  "1",
+		Constraints: []*schema.Constraint{
+			{Condition: "var.app.name != \"x\"", ErrorMessage: "Name must not be x."},
+		},
+		SchemaNodes: map[string]*schema.Node{
+			"port": {
+				Marinate: &schema.MarinateInfo{
+					Description: "Listening port",
+					Constraints: []*schema.Constraint{
+						{Condition: "var.app.port > 0 &&\n    var.app.port < 65536"},
+					},
+				},
+				Attributes: map[string]*schema.Node{},
+			},
+		},
+	}
+
+	result, err := NewRenderer().RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := "- `port` - (Optional) Listening port\n" +
+		"  - Constraint: `var.app.port > 0 && var.app.port < 65536`\n" +
+		"\n" +
+		"Constraints:\n" +
+		"\n" +
+		"- Name must not be x. (`var.app.name != \"x\"`)\n"
+	if result != want {
+		t.Errorf("Expected:\n%s\nGot:\n%s", want, result)
+	}
+}
+
+func TestRenderSchema_ValueRules(t *testing.T) {
+	low, high := 1.0, 10.5
+	s := &schema.Schema{
+		Variable: "app",
+		Version:  "1",
+		SchemaNodes: map[string]*schema.Node{
+			"count": {
+				Marinate:   &schema.MarinateInfo{Description: "Instances", Min: &low, Max: &high},
+				Attributes: map[string]*schema.Node{},
+			},
+			"mode": {
+				Marinate:   &schema.MarinateInfo{Description: "Mode", AllowedValues: []any{"dev", "prod"}},
+				Attributes: map[string]*schema.Node{},
+			},
+			"name": {
+				Marinate:   &schema.MarinateInfo{Description: "Name", Pattern: "^[a-z]+$"},
+				Attributes: map[string]*schema.Node{},
+			},
+			"zones": {
+				Marinate: &schema.MarinateInfo{
+					Description:   "Zones",
+					AllowedValues: []any{[]any{"1", "2"}, map[string]any{"primary": "1"}},
+				},
+				Attributes: map[string]*schema.Node{},
+			},
+		},
+	}
+
+	cfg := DefaultTemplateConfig()
+	cfg.AttributeTemplate = "{{.Attribute}} - {{.Description}}" +
+		"{{if .HasAllowedValues}} Allowed: {{.AllowedValues}}{{end}}" +
+		"{{if .HasPattern}} Pattern: `{{.Pattern}}`{{end}}" +
+		"{{if .HasMin}} Min: {{.Min}}{{end}}{{if .HasMax}} Max: {{.Max}}{{end}}"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	result, err := NewRendererWithTemplate(cfg).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Allowed values are literals in the value format, like defaults
+	want := "- `count` - Instances Min: 1 Max: 10.5\n" +
+		"- `mode` - Mode Allowed: `\"dev\"`, `\"prod\"`\n" +
+		"- `name` - Name Pattern: `^[a-z]+$`\n" +
+		"- `zones` - Zones Allowed: `[\"1\", \"2\"]`, `{ primary = \"1\" }`\n"
+	if result != want {
+		t.Errorf("Expected:\n%s\nGot:\n%s", want, result)
+	}
+
+	cfg.ValueFormat = ValueFormatJSON
+	result, err = NewRendererWithTemplate(cfg).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(result, "Allowed: `[\"1\",\"2\"]`, `{\"primary\":\"1\"}`") {
+		t.Errorf("Expected JSON allowed values, got:\n%s", result)
+	}
+}
+
+func TestRenderSchema_EffectiveDefaults(t *testing.T) {
+	s := &schema.Schema{
+		Variable: "app",
+		Version:  "1",
+		Default:  map[string]any{"name": "web"},
+		SchemaNodes: map[string]*schema.Node{
+			"name": {
+				Marinate:   &schema.MarinateInfo{Description: "Name", Required: true, EffectiveDefault: "web"},
+				Attributes: map[string]*schema.Node{},
+			},
+			"port": {
+				Marinate:   &schema.MarinateInfo{Description: "Port", Default: 8080, EffectiveDefault: 8080},
+				Attributes: map[string]*schema.Node{},
+			},
+		},
+	}
+
+	result, err := NewRenderer().RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The port's effective default is its own default and adds nothing
+	want := "- `name` - (Required) Name\n" +
+		"  - Effective default: `\"web\"`\n" +
+		"- `port` - (Optional) Port\n" +
+		"\n" +
+		"Default: `{ name = \"web\" }`\n"
+	if result != want {
+		t.Errorf("Expected:\n%s\nGot:\n%s", want, result)
+	}
+}
+
+func TestRenderSchema_LongValuesAsCodeBlocks(t *testing.T) {
+	s := &schema.Schema{
+		Variable: "app",
+		Version:  "1",
+		SchemaNodes: map[string]*schema.Node{
+			"tags": {
+				Marinate: &schema.MarinateInfo{
+					Description: "Tags",
+					Default:     map[string]any{"environment": "production", "owner": "platform-team"},
+					Example:     []any{"a"},
+				},
+				Attributes: map[string]*schema.Node{},
+			},
+		},
+	}
+
+	cfg := DefaultTemplateConfig()
+	cfg.AttributeTemplate = "{{.Attribute}} - {{.Description}}{{if .HasDefault}} Default: {{code .Default}}{{end}}" +
+		"{{if .HasExample}} Example: {{code .Example}}{{end}}"
+	cfg.CodeBlockThreshold = 40
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	result, err := NewRendererWithTemplate(cfg).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The default is longer than the threshold and continues as a code block in the list item
+	want := "- `tags` - Tags Default:\n" +
+		"  ```hcl\n" +
+		"  {\n" +
+		"    environment = \"production\"\n" +
+		"    owner       = \"platform-team\"\n" +
+		"  }\n" +
+		"  ```\n" +
+		"   Example: `[\"a\"]`\n"
+	if result != want {
+		t.Errorf("Expected:\n%s\nGot:\n%s", want, result)
+	}
+
+	cfg.ValueFormat = ValueFormatJSON
+	cfg.CodeBlockThreshold = 0
+	result, err = NewRendererWithTemplate(cfg).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(result, "Default: `{\"environment\":\"production\",\"owner\":\"platform-team\"}`") {
+		t.Errorf("Expected a single-line JSON default, got:\n%s", result)
+	}
+}
+
+// TestRenderSchema_TableMode tests that table mode renders one row per attribute with a dotted
+// path, escapes pipes and newlines, and lists attribute validations after the table.
+func TestRenderSchema_TableMode(t *testing.T) {
+	s := &schema.Schema{
+		Variable: "app",
+		Version:  "1",
+		SchemaNodes: map[string]*schema.Node{
+			"database": {
+				Marinate: &schema.MarinateInfo{Description: "Database settings", Type: "object"},
+				Attributes: map[string]*schema.Node{
+					"port": {
+						Marinate: &schema.MarinateInfo{
+							Description:      "Port, either 5432 | 6432.\nDefaults to PostgreSQL.",
+							Type:             "number",
+							Default:          5432,
+							EffectiveDefault: 6432,
+							Constraints: []*schema.Constraint{
+								{Condition: "var.app.database.port > 0", ErrorMessage: "Port must be positive."},
+							},
+						},
+					},
+				},
+			},
+			"pair": {
+				Marinate: &schema.MarinateInfo{Type: "tuple", Required: true},
+				Attributes: map[string]*schema.Node{
+					"_0": {Marinate: &schema.MarinateInfo{Description: "First", Type: "string"}},
+				},
+			},
+		},
+	}
+
+	cfg := DefaultTemplateConfig()
+	cfg.RenderMode = RenderModeTable
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	result, err := NewRendererWithTemplate(cfg).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := "| Path | Type | Required | Default | Effective default | Description |\n" +
+		"| --- | --- | --- | --- | --- | --- |\n" +
+		"| `database` | object | Optional |  |  | Database settings |\n" +
+		"| `database.port` | number | Optional | `5432` | `6432` | Port, either 5432 \\| 6432.<br>Defaults to PostgreSQL. |\n" +
+		"| `pair` | tuple | Required |  |  |  |\n" +
+		"| `pair[0]` | string | Optional |  |  | First |\n" +
+		"\n" +
+		"Constraints:\n\n" +
+		"- `database.port`: Port must be positive. (`var.app.database.port > 0`)\n"
+	if result != want {
+		t.Errorf("Expected:\n%s\nGot:\n%s", want, result)
+	}
+
+	cfg.TableColumns = []TableColumn{
+		{Header: "Attribute", Template: "{{.Attribute}}"},
+		{Header: "Default | Example", Template: "{{code .Default}}"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	result, err = NewRendererWithTemplate(cfg).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(result, "| Attribute | Default \\| Example |\n| --- | --- |\n| `database` |  |\n| `port` | `5432` |\n") {
+		t.Errorf("Expected custom columns, got:\n%s", result)
+	}
+}
+
+func TestRenderSchema_TableModeCollectionPaths(t *testing.T) {
+	s := &schema.Schema{
+		Variable: "network",
+		Version:  "1",
+		SchemaNodes: map[string]*schema.Node{
+			"rules": {
+				Marinate: &schema.MarinateInfo{Description: "Rules", Type: "map", ValueType: "object"},
+				Attributes: map[string]*schema.Node{
+					"port": {Marinate: &schema.MarinateInfo{Description: "Port", Type: "number"}},
+				},
+			},
+			"subnets": {
+				Marinate: &schema.MarinateInfo{Description: "Subnets", Type: "list", ElementType: "object"},
+				Attributes: map[string]*schema.Node{
+					"name": {Marinate: &schema.MarinateInfo{Description: "Name", Type: "string"}},
+				},
+			},
+			"zones": {
+				Marinate: &schema.MarinateInfo{Description: "Zones", Type: "map", ValueType: "map"},
+				Attributes: map[string]*schema.Node{
+					"_values": {
+						Marinate: &schema.MarinateInfo{Type: "map", ValueType: "object"},
+						Attributes: map[string]*schema.Node{
+							"ttl": {Marinate: &schema.MarinateInfo{Description: "TTL", Type: "number"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cfg := DefaultTemplateConfig()
+	cfg.RenderMode = RenderModeTable
+	cfg.TableColumns = []TableColumn{{Header: "Path", Template: "{{.Path}}"}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	result, err := NewRendererWithTemplate(cfg).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Attributes of collection elements are addressed through the element
+	want := "| Path |\n| --- |\n" +
+		"| `rules` |\n| `rules.*.port` |\n" +
+		"| `subnets` |\n| `subnets[*].name` |\n" +
+		"| `zones` |\n| `zones.*` |\n| `zones.*.*.ttl` |\n"
+	if result != want {
+		t.Errorf("Expected:\n%s\nGot:\n%s", want, result)
+	}
+}
+
+func TestRenderSchema_HeadingsMode(t *testing.T) {
+	s := &schema.Schema{
+		Variable: "app",
+		Version:  "1",
+		Default:  map[string]any{"name": "web"},
+		Constraints: []*schema.Constraint{
+			{Condition: "var.app.name != \"x\"", ErrorMessage: "Name must not be x."},
+		},
+		SchemaNodes: map[string]*schema.Node{
+			"name": {Marinate: &schema.MarinateInfo{Description: "App name", Type: "string", Required: true}},
+			"database": {
+				Marinate: &schema.MarinateInfo{Description: "Database settings", Type: "object"},
+				Attributes: map[string]*schema.Node{
+					"port": {Marinate: &schema.MarinateInfo{
+						Description: "Port",
+						Type:        "number",
+						Default:     5432,
+						Constraints: []*schema.Constraint{{Condition: "var.app.database.port > 0"}},
+					}},
+					"tls": {
+						Marinate: &schema.MarinateInfo{Description: "TLS settings", Type: "object"},
+						Attributes: map[string]*schema.Node{
+							"enabled": {Marinate: &schema.MarinateInfo{Description: "Enable TLS", Type: "bool"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cfg := DefaultTemplateConfig()
+	cfg.RenderMode = RenderModeHeadings
+	cfg.HeadingLevel = 5
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	result, err := NewRendererWithTemplate(cfg).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The variable's default and constraints stay above the first heading; headings deeper
+	// than h6 stay at h6
+	want := "- `name` - (Required) App name\n" +
+		"\n" +
+		"Default: `{ name = \"web\" }`\n" +
+		"\n" +
+		"Constraints:\n" +
+		"\n" +
+		"- Name must not be x. (`var.app.name != \"x\"`)\n" +
+		"\n" +
+		"<a name=\"app-database\"></a>\n" +
+		"##### `database`\n" +
+		"\n" +
+		"`database` - (Optional) Database settings\n" +
+		"\n" +
+		"- `port` - (Optional) Port\n" +
+		"  - Constraint: `var.app.database.port > 0`\n" +
+		"\n" +
+		"<a name=\"app-database-tls\"></a>\n" +
+		"###### `database.tls`\n" +
+		"\n" +
+		"`tls` - (Optional) TLS settings\n" +
+		"\n" +
+		"- `enabled` - (Optional) Enable TLS\n"
+	if result != want {
+		t.Errorf("Expected:\n%s\nGot:\n%s", want, result)
+	}
+
+	cfg.HeadingLeaves = RenderModeTable
+	result, err = NewRendererWithTemplate(cfg).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(result, "\n\n| Path | Type | Required | Default | Effective default | Description |\n"+
+		"| --- | --- | --- | --- | --- | --- |\n"+
+		"| `database.port` | number | Optional | `5432` |  | Port |\n\n"+
+		"Constraints:\n\n- `database.port`: `var.app.database.port > 0`\n\n<a name=\"app-database-tls\">") {
+		t.Errorf("Expected a table and its constraints below the database heading, got:\n%s", result)
+	}
+
+	// Terraform descriptions cannot hold headings
+	result, err = NewTerraformRenderer(cfg).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(result, "#") || !strings.Contains(result, "  - `tls` - (Optional) TLS settings\n") {
+		t.Errorf("Expected the list mode in Terraform descriptions, got:\n%s", result)
+	}
+}
+
+func TestRenderSchema_CollapsedAttributes(t *testing.T) {
+	s := &schema.Schema{
+		Variable: "network",
+		Version:  "1",
+		SchemaNodes: map[string]*schema.Node{
+			"rules": {
+				Marinate: &schema.MarinateInfo{Description: "Firewall <rules>", Type: "map"},
+				Attributes: map[string]*schema.Node{
+					"port": {Marinate: &schema.MarinateInfo{Description: "Port", Type: "number"}},
+					"source": {
+						Marinate: &schema.MarinateInfo{Description: "Source", Type: "object"},
+						Attributes: map[string]*schema.Node{
+							"cidr": {Marinate: &schema.MarinateInfo{Description: "CIDR", Type: "string"}},
+						},
+					},
+				},
+			},
+			"name": {Marinate: &schema.MarinateInfo{Description: "Name", Type: "string", Required: true}},
+		},
+		Default: "x",
+	}
+
+	cfg := DefaultTemplateConfig()
+	cfg.CollapseDepth = 2
+	cfg.CollapseSummary = "{{.Description}}: {{.Count}} at depth {{.Depth}}"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	result, err := NewRendererWithTemplate(cfg).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Blank lines around the tags keep the markdown inside them rendered
+	want := "- `name` - (Required) Name\n" +
+		"- `rules` - (Optional) Firewall <rules>\n" +
+		"  - `port` - (Optional) Port\n" +
+		"  - `source` - (Optional) Source\n" +
+		"\n" +
+		"    <details><summary>Source: 1 at depth 2</summary>\n" +
+		"\n" +
+		"    - `cidr` - (Optional) CIDR\n" +
+		"\n" +
+		"    </details>\n" +
+		"\n" +
+		"Default: `\"x\"`\n"
+	if result != want {
+		t.Errorf("Expected:\n%s\nGot:\n%s", want, result)
+	}
+
+	// Attributes with many attributes collapse at any depth; nested blocks nest
+	cfg.CollapseThreshold = 2
+	cfg.CollapseSummary = ""
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	s.Default = nil
+	result, err = NewRendererWithTemplate(cfg).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(result, "- `rules` - (Optional) Firewall <rules>\n\n  <details><summary>2 attributes of rules</summary>\n\n") ||
+		!strings.HasSuffix(result, "    </details>\n\n  </details>\n") {
+		t.Errorf("Expected nested collapsed attributes, got:\n%s", result)
+	}
+}
+
+func TestRenderSchema_SensitiveValuesMasked(t *testing.T) {
+	s := &schema.Schema{
+		Variable:  "db",
+		Version:   "1",
+		Sensitive: true,
+		SchemaNodes: map[string]*schema.Node{
+			"password": {
+				Marinate: &schema.MarinateInfo{
+					Description: "Admin password",
+					Default:     "changeme",
+					Example:     "s3cret",
+				},
+				Attributes: map[string]*schema.Node{},
+			},
+		},
+	}
+
+	cfg := DefaultTemplateConfig()
+	cfg.AttributeTemplate = "{{.Attribute}} - {{.Description}} - Default: {{.Default}} - Example: {{.Example}}" +
+		"{{if .Sensitive}} (sensitive variable){{end}}"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	result, err := NewRendererWithTemplate(cfg).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := "- `password` - Admin password - Default: (sensitive) - Example: (sensitive) (sensitive variable)\n"
+	if result != want {
+		t.Errorf("Expected:\n%s\nGot:\n%s", want, result)
+	}
+
+	// Explicitly allowed in the variable's YAML config
+	s.Config = &schema.VariableConfig{ShowSensitiveValues: true}
+	result, err = NewRendererWithTemplate(cfg).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(result, `Default: "changeme" - Example: "s3cret"`) {
+		t.Errorf("Expected values to be shown, got:\n%s", result)
+	}
+}
+
 func TestRenderSchema_ShowDescriptionExplicitlyTrue(t *testing.T) {
 	// When ShowDescription is explicitly true, description should be shown
 	showDesc := true
@@ -516,10 +1067,10 @@ func TestRenderSchema_WithDefaultAndExample(t *testing.T) {
 	if !strings.Contains(result, "[Example: 3306]") {
 		t.Error("Expected example value 3306 for port")
 	}
-	if !strings.Contains(result, "[Default: localhost]") {
+	if !strings.Contains(result, `[Default: "localhost"]`) {
 		t.Error("Expected default value 'localhost' for host")
 	}
-	if !strings.Contains(result, "[Example: db.example.com]") {
+	if !strings.Contains(result, `[Example: "db.example.com"]`) {
 		t.Error("Expected example value 'db.example.com' for host")
 	}
 }
@@ -590,7 +1141,7 @@ func TestRenderSchema_WithEmptyStringDefault(t *testing.T) {
 	}
 
 	// Check that non-empty default is shown
-	if !strings.Contains(result, `[Default: prod]`) {
+	if !strings.Contains(result, `[Default: "prod"]`) {
 		t.Errorf("Expected default value 'prod' for suffix. Got:\n%s", result)
 	}
 
@@ -807,3 +1358,115 @@ func TestRenderSchema_ConditionalShowDescription(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderSchema_TuplePositionsInOrder(t *testing.T) {
+	positions := map[string]*schema.Node{}
+	for i := range 11 {
+		positions[schema.TupleElementKey(i)] = &schema.Node{
+			Marinate: &schema.MarinateInfo{
+				Description: "Position",
+				Type:        "string",
+				Required:    true,
+			},
+			Attributes: map[string]*schema.Node{},
+		}
+	}
+
+	s := &schema.Schema{
+		Variable:    "ranges",
+		Version:     "1",
+		Marinate:    &schema.MarinateInfo{Description: "Ranges", Type: "tuple", Required: true},
+		SchemaNodes: positions,
+	}
+
+	r := NewRenderer()
+	result, err := r.RenderSchema(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	idx2 := strings.Index(result, "`_2`")
+	idx10 := strings.Index(result, "`_10`")
+	if idx2 == -1 || idx10 == -1 {
+		t.Fatalf("Expected tuple positions in output, got:\n%s", result)
+	}
+	if idx2 > idx10 {
+		t.Errorf("Expected _2 to be rendered before _10, got:\n%s", result)
+	}
+}
+
+func TestRenderSchema_RootLeadIn(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     *schema.MarinateInfo
+		children bool
+		want     string
+	}{
+		{
+			name:     "map of objects with key description",
+			info:     &schema.MarinateInfo{Type: "map", ValueType: "object", KeyDescription: "rule name"},
+			children: true,
+			want:     "Map of objects keyed by rule name, each with:\n\n- `name`",
+		},
+		{
+			name:     "list of objects",
+			info:     &schema.MarinateInfo{Type: "list", ElementType: "object"},
+			children: true,
+			want:     "List of objects, each with:\n\n- `name`",
+		},
+		{
+			name: "list of strings",
+			info: &schema.MarinateInfo{Type: "list", ElementType: "string"},
+			want: "List of strings.\n",
+		},
+		{
+			name:     "description before lead-in",
+			info:     &schema.MarinateInfo{Type: "set", ElementType: "object", Description: "Firewall rules."},
+			children: true,
+			want:     "Firewall rules.\n\nSet of objects, each with:",
+		},
+		{
+			name: "TODO description skipped",
+			info: &schema.MarinateInfo{Type: "list", ElementType: "string", Description: "# TODO: Add description for x"},
+			want: "List of strings.",
+		},
+		{
+			name: "simple type",
+			info: &schema.MarinateInfo{Type: "string", Description: "Azure region."},
+			want: "Azure region.\n\nType: `string`\n",
+		},
+		{
+			name: "simple type with TODO description",
+			info: &schema.MarinateInfo{Type: "bool", Description: "# TODO: Add description for x"},
+			want: "Type: `bool`\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &schema.Schema{
+				Variable:    "rules",
+				Version:     "1",
+				Marinate:    tt.info,
+				SchemaNodes: map[string]*schema.Node{},
+			}
+			if tt.children {
+				s.SchemaNodes["name"] = &schema.Node{
+					Marinate:   &schema.MarinateInfo{Description: "The name", Type: "string", Required: true},
+					Attributes: map[string]*schema.Node{},
+				}
+			}
+
+			result, err := NewRenderer().RenderSchema(s)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.HasPrefix(result, tt.want) {
+				t.Errorf("Expected output to start with %q, got:\n%s", tt.want, result)
+			}
+			if strings.Contains(result, "TODO") {
+				t.Errorf("Expected TODO root description to be skipped, got:\n%s", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package markdown
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/glueckkanja/marinatemd/internal/schema"
+)
+
+// tableCellEscaper keeps cell content inside its cell: pipes would start a new cell and
+// newlines would end the row.
+var tableCellEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+// escapeTableCell formats text as the content of a table cell.
+func escapeTableCell(s string) string {
+	return tableCellEscaper.Replace(strings.TrimSpace(s))
+}
+
+// RenderTableHeader returns the header and delimiter rows of the attribute table.
+func (tc *TemplateConfig) RenderTableHeader() string {
+	columns := tc.columns()
+	headers := make([]string, len(columns))
+	delimiters := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = escapeTableCell(column.Header)
+		delimiters[i] = "---"
+	}
+	return tableRow(headers) + "\n" + tableRow(delimiters)
+}
+
+// RenderTableRow applies the column templates to a context and returns the table row.
+// The attribute name and path are escaped like in RenderAttribute.
+func (tc *TemplateConfig) RenderTableRow(ctx TemplateContext) string {
+	if tc.compiledColumns == nil {
+		if err := tc.compileTemplate(); err != nil {
+			return ""
+		}
+	}
+
+	ctx.Attribute = tc.escape(ctx.Attribute)
+	ctx.Path = tc.escape(ctx.Path)
+
+	cells := make([]string, len(tc.compiledColumns))
+	for i, tmpl := range tc.compiledColumns {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, ctx); err != nil {
+			// A broken column leaves its cell empty rather than the whole table
+			continue
+		}
+		cells[i] = escapeTableCell(buf.String())
+	}
+	return tableRow(cells)
+}
+
+func tableRow(cells []string) string {
+	return "| " + strings.Join(cells, " | ") + " |"
+}
+
+// renderTable renders the nodes as a table with one row per documented attribute, in the
+// same order as the list: every attribute is followed by its own attributes. Paths start at
+// parentPath. Returns the validations of the attributes, formatted like variable constraints
+// and prefixed with the path.
+func (r *Renderer) renderTable(
+	nodes map[string]*schema.Node,
+	parentPath string,
+	vars *variableContext,
+	builder *strings.Builder,
+) []string {
+	var rows, constraints []string
+	r.collectTableRows(nodes, parentPath, vars, &rows, &constraints)
+	if len(rows) == 0 {
+		return constraints
+	}
+
+	builder.WriteString(r.templateCfg.RenderTableHeader())
+	builder.WriteString("\n")
+	for _, row := range rows {
+		builder.WriteString(row)
+		builder.WriteString("\n")
+	}
+	return constraints
+}
+
+func (r *Renderer) collectTableRows(
+	nodes map[string]*schema.Node,
+	parentPath string,
+	vars *variableContext,
+	rows, constraints *[]string,
+) {
+	for _, name := range schema.SortedAttributeNames(nodes) {
+		node := nodes[name]
+		if node == nil {
+			continue
+		}
+		path := tablePath(parentPath, name)
+
+		if node.Marinate != nil {
+			ctx := r.newTemplateContext(name, node.Marinate, vars, "")
+			ctx.Path = path
+			if ctx.Description != "" || ctx.HasType || ctx.IsRequired || len(node.Attributes) > 0 {
+				*rows = append(*rows, r.templateCfg.RenderTableRow(ctx))
+			}
+			for _, constraint := range node.Marinate.Constraints {
+				*constraints = append(*constraints, "`"+path+"`: "+formatConstraint(constraint))
+			}
+		}
+
+		r.collectTableRows(node.Attributes, elementPath(path, node.Marinate), vars, rows, constraints)
+	}
+}
+
+// elementPath appends the step into the elements of a collection to its path, so the paths of
+// the element's attributes exist: items[*].name for lists and sets, rules.*.port for maps.
+// Nested maps take the step with their _values node.
+func elementPath(path string, info *schema.MarinateInfo) string {
+	if info == nil {
+		return path
+	}
+	switch {
+	case info.Type == "list", info.Type == "set":
+		return path + "[*]"
+	case info.Type == "map" && info.ValueType != "map":
+		return path + ".*"
+	default:
+		return path
+	}
+}
+
+// tablePath appends an attribute to a dotted path. Tuple positions become indexes (pair[0])
+// and the values of nested maps become * (zones.*.*).
+func tablePath(parent, name string) string {
+	if index, ok := schema.TupleElementIndex(name); ok {
+		return parent + "[" + strconv.Itoa(index) + "]"
+	}
+	if name == "_values" {
+		name = "*"
+	}
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
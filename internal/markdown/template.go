@@ -11,14 +11,70 @@ import (
 const (
 	// DefaultIndentSize is the default number of spaces per indent level.
 	DefaultIndentSize = 2
+
+	// DefaultSensitiveMask replaces defaults and examples of sensitive variables.
+	DefaultSensitiveMask = "(sensitive)"
+
+	// DefaultCodeBlockThreshold is the length above which list and object values become code blocks.
+	DefaultCodeBlockThreshold = 80
+
+	// DefaultHeadingLevel is the level of the top-level headings in headings render mode.
+	DefaultHeadingLevel = 4
+
+	// DefaultCollapseSummary is the summary of collapsed attributes.
+	DefaultCollapseSummary = "{{.Count}} attributes of {{.Attribute}}"
+
+	// maxHeadingLevel is the deepest markdown heading; deeper attributes stay at this level.
+	maxHeadingLevel = 6
 )
 
+// Render modes.
+const (
+	// RenderModeList renders attributes as a nested list, one AttributeTemplate line each.
+	RenderModeList = "list"
+
+	// RenderModeTable renders attributes as a table with one row per attribute.
+	RenderModeTable = "table"
+
+	// RenderModeHeadings renders attributes with nested attributes as headings, with the
+	// other attributes as a list or table below them.
+	RenderModeHeadings = "headings"
+)
+
+// TableColumn is a column of the attribute table in table render mode.
+type TableColumn struct {
+	// Header is the column heading.
+	Header string `mapstructure:"header" yaml:"header"`
+
+	// Template renders the cell of an attribute. It has the same fields and functions as
+	// AttributeTemplate; .Path is the dotted path of the attribute, e.g. database.port.
+	Template string `mapstructure:"template" yaml:"template"`
+}
+
+// DefaultTableColumns returns the columns used when TableColumns is empty.
+func DefaultTableColumns() []TableColumn {
+	return []TableColumn{
+		{Header: "Path", Template: "{{.Path}}"},
+		{Header: "Type", Template: "{{.Type}}"},
+		{Header: "Required", Template: "{{.Required}}"},
+		{Header: "Default", Template: "{{code .Default}}"},
+		{Header: "Effective default", Template: "{{if ne .EffectiveDefault .Default}}{{code .EffectiveDefault}}{{end}}"},
+		{Header: "Description", Template: "{{.Description}}"},
+	}
+}
+
 // TemplateConfig defines how markdown is generated from schema fields.
 type TemplateConfig struct {
 	// AttributeTemplate defines the format for rendering individual attributes.
 	// Supports Go template syntax with conditionals and functions.
 	// Available fields: .Attribute, .Required, .Description, .Type, .Default, .Example
-	// Available booleans: .IsRequired, .HasDefault, .HasExample, .HasType
+	// Available booleans: .IsRequired, .HasDefault, .HasExample, .HasType, .NeedsReview
+	// Default when the variable is omitted: .EffectiveDefault (with .HasEffectiveDefault)
+	// Value rules from validations: .AllowedValues, .Pattern, .Min, .Max (with .HasAllowedValues etc.)
+	// Variable attributes: .Sensitive, .Nullable, .Ephemeral
+	//
+	// Values (.Default, .Example, .EffectiveDefault) are literals in ValueFormat. Long values are
+	// fenced code blocks starting on a new line; {{code .Default}} adds backticks to short values only.
 	//
 	// Simple placeholders (legacy, auto-converted):
 	//   {attribute}, {required}, {description}, {type}, {default}, {example}
@@ -56,29 +112,111 @@ type TemplateConfig struct {
 	// Example: [0, 2] will add separators at depth 0 and depth 2
 	SeparatorIndents []int `mapstructure:"separator_indents" yaml:"separator_indents"`
 
+	// ReviewBadge is appended to attributes whose description is marked needs_review.
+	// Empty means no badge; templates can still check .NeedsReview themselves.
+	// Example: "⚠️ *needs review*"
+	// Default: "" (no badge)
+	ReviewBadge string `mapstructure:"review_badge" yaml:"review_badge"`
+
+	// SensitiveMask replaces defaults and examples of sensitive variables, unless the
+	// variable's YAML config sets show_sensitive_values: true. Empty means the default.
+	// Default: "(sensitive)"
+	SensitiveMask string `mapstructure:"sensitive_mask" yaml:"sensitive_mask"`
+
+	// ValueFormat is the syntax defaults and examples are rendered in.
+	// Options: "hcl" ({ a = 1, b = ["x"] }), "json" ({"a":1,"b":["x"]})
+	// Default: "hcl"
+	ValueFormat string `mapstructure:"value_format" yaml:"value_format"`
+
+	// CodeBlockThreshold is the length above which list and object values are rendered
+	// as multi-line fenced code blocks instead of a single line. 0 disables code blocks.
+	// Default: 80
+	CodeBlockThreshold int `mapstructure:"code_block_threshold" yaml:"code_block_threshold"`
+
+	// RenderMode selects how attributes are laid out.
+	// Options: "list" (nested list), "table" (one row per attribute with a dotted path),
+	// "headings" (a heading per attribute with nested attributes; markdown injection only,
+	// Terraform descriptions use "list")
+	// Default: "list"
+	RenderMode string `mapstructure:"render_mode" yaml:"render_mode"`
+
+	// HeadingLevel is the level of the top-level headings in headings render mode. Nested
+	// attributes get one level more, up to h6. 0 means the default.
+	// Default: 4
+	HeadingLevel int `mapstructure:"heading_level" yaml:"heading_level"`
+
+	// HeadingLeaves selects how the attributes below a heading are laid out in headings render mode.
+	// Options: "list", "table" (with TableColumns)
+	// Default: "list"
+	HeadingLeaves string `mapstructure:"heading_leaves" yaml:"heading_leaves"`
+
+	// TableColumns are the columns of the attribute table in table render mode.
+	// Empty means the default columns: Path, Type, Required, Default and Description.
+	// Cells are rendered on a single line; pipes are escaped and newlines become <br>.
+	TableColumns []TableColumn `mapstructure:"table_columns" yaml:"table_columns"`
+
+	// CollapseDepth wraps the attributes at this depth and deeper in collapsible <details>
+	// blocks in list mode. Top-level attributes are at depth 0. 0 disables it.
+	// Default: 0
+	CollapseDepth int `mapstructure:"collapse_depth" yaml:"collapse_depth"`
+
+	// CollapseThreshold wraps the attributes of an attribute in a collapsible <details> block
+	// in list mode when it has at least this many. 0 disables it.
+	// Default: 0
+	CollapseThreshold int `mapstructure:"collapse_threshold" yaml:"collapse_threshold"`
+
+	// CollapseSummary is the Go template of the <summary> of collapsed attributes.
+	// Available fields: .Attribute, .Description, .Type (of the attribute whose attributes are
+	// collapsed), .Count (number of collapsed attributes), .Depth (their depth)
+	// Default: "{{.Count}} attributes of {{.Attribute}}"
+	CollapseSummary string `mapstructure:"collapse_summary" yaml:"collapse_summary"`
+
 	// compiledTemplate holds the parsed Go template (internal use)
 	compiledTemplate *template.Template
+
+	// compiledColumns holds the parsed column templates (internal use)
+	compiledColumns []*template.Template
+
+	// compiledSummary holds the parsed summary template (internal use)
+	compiledSummary *template.Template
 }
 
 // DefaultTemplateConfig returns the default template configuration.
 func DefaultTemplateConfig() *TemplateConfig {
 	cfg := &TemplateConfig{
-		AttributeTemplate: "{{.Attribute}} - ({{.Required}}) {{.Description}}",
-		RequiredText:      "Required",
-		OptionalText:      "Optional",
-		EscapeMode:        "inline_code",
-		IndentStyle:       "bullets",
-		IndentSize:        DefaultIndentSize,
-		SeparatorIndents:  []int{}, // No separators by default
+		AttributeTemplate:  "{{.Attribute}} - ({{.Required}}) {{.Description}}",
+		RequiredText:       "Required",
+		OptionalText:       "Optional",
+		EscapeMode:         "inline_code",
+		IndentStyle:        "bullets",
+		IndentSize:         DefaultIndentSize,
+		SeparatorIndents:   []int{}, // No separators by default
+		SensitiveMask:      DefaultSensitiveMask,
+		ValueFormat:        ValueFormatHCL,
+		CodeBlockThreshold: DefaultCodeBlockThreshold,
+		RenderMode:         RenderModeList,
+		HeadingLevel:       DefaultHeadingLevel,
+		HeadingLeaves:      RenderModeList,
+		CollapseSummary:    DefaultCollapseSummary,
 	}
 	// Compile the template immediately
 	_ = cfg.compileTemplate()
 	return cfg
 }
 
+// SummaryContext holds the data for rendering the summary of collapsed attributes.
+type SummaryContext struct {
+	Attribute   string // Attribute whose attributes are collapsed
+	Description string
+	Type        string
+	Count       int // Number of collapsed attributes
+	Depth       int // Depth of the collapsed attributes
+}
+
 // TemplateContext holds the data for rendering a single attribute.
 type TemplateContext struct {
 	Attribute       string
+	Path            string // Dotted path from the variable, e.g. database.port (table mode)
 	Required        string // String representation ("Required" or "Optional")
 	IsRequired      bool   // Boolean flag for conditional checks
 	Description     string
@@ -89,6 +227,27 @@ type TemplateContext struct {
 	HasDefault      bool // Helper for conditionals
 	HasExample      bool // Helper for conditionals
 	HasType         bool // Helper for conditionals
+	NeedsReview     bool // Whether the description is marked needs_review
+
+	// EffectiveDefault is the value the attribute gets when the whole variable is omitted,
+	// taking the variable-level default into account
+	EffectiveDefault    string
+	HasEffectiveDefault bool // Helper for conditionals
+
+	// Value rules recognised from validation blocks
+	AllowedValues    string // Allowed values as inline code, e.g. "`a`, `b`"
+	Pattern          string // Regular expression the value must match
+	Min              string // Inclusive minimum
+	Max              string // Inclusive maximum
+	HasAllowedValues bool   // Helper for conditionals
+	HasPattern       bool   // Helper for conditionals
+	HasMin           bool   // Helper for conditionals
+	HasMax           bool   // Helper for conditionals
+
+	// Variable-level attributes, the same for every attribute of a variable
+	Sensitive bool // Variable is declared sensitive; Default and Example are masked
+	Nullable  bool // Variable accepts null (false for nullable = false)
+	Ephemeral bool // Variable is declared ephemeral
 }
 
 // compileTemplate compiles the attribute template into a Go template.
@@ -98,19 +257,59 @@ func (tc *TemplateConfig) compileTemplate() error {
 	templateStr := tc.convertLegacyPlaceholders(tc.AttributeTemplate)
 
 	// Create template with helper functions
-	tmpl := template.New("attribute").Funcs(template.FuncMap{
+	funcs := template.FuncMap{
 		"escape": tc.escape,
-	})
+		"code":   codeSpan,
+	}
 
 	var err error
-	tc.compiledTemplate, err = tmpl.Parse(templateStr)
+	tc.compiledTemplate, err = template.New("attribute").Funcs(funcs).Parse(templateStr)
 	if err != nil {
 		return fmt.Errorf("failed to compile template: %w", err)
 	}
 
+	columns := tc.columns()
+	tc.compiledColumns = make([]*template.Template, len(columns))
+	for i, column := range columns {
+		tc.compiledColumns[i], err = template.New(column.Header).Funcs(funcs).Parse(column.Template)
+		if err != nil {
+			return fmt.Errorf("failed to compile template of column %q: %w", column.Header, err)
+		}
+	}
+
+	summary := tc.CollapseSummary
+	if summary == "" {
+		summary = DefaultCollapseSummary
+	}
+	tc.compiledSummary, err = template.New("summary").Funcs(funcs).Parse(summary)
+	if err != nil {
+		return fmt.Errorf("failed to compile collapse_summary: %w", err)
+	}
+
 	return nil
 }
 
+// headingLevel returns the configured level of top-level headings, or the default if none is configured.
+func (tc *TemplateConfig) headingLevel() int {
+	if tc.HeadingLevel == 0 {
+		return DefaultHeadingLevel
+	}
+	return tc.HeadingLevel
+}
+
+// usesTables reports whether attributes are rendered as table rows, which hold a single line.
+func (tc *TemplateConfig) usesTables() bool {
+	return tc.RenderMode == RenderModeTable || (tc.RenderMode == RenderModeHeadings && tc.HeadingLeaves == RenderModeTable)
+}
+
+// columns returns the configured table columns, or the default columns if none are configured.
+func (tc *TemplateConfig) columns() []TableColumn {
+	if len(tc.TableColumns) == 0 {
+		return DefaultTableColumns()
+	}
+	return tc.TableColumns
+}
+
 // convertLegacyPlaceholders converts old {placeholder} syntax to {{.Field}} syntax
 // for backward compatibility.
 func (tc *TemplateConfig) convertLegacyPlaceholders(tmpl string) string {
@@ -249,5 +448,46 @@ func (tc *TemplateConfig) Validate() error {
 		return errors.New("indent_size must be non-negative")
 	}
 
+	// Validate value format; empty means the default
+	switch tc.ValueFormat {
+	case "", ValueFormatHCL, ValueFormatJSON:
+	default:
+		return fmt.Errorf("invalid value_format: %s (valid options: hcl, json)", tc.ValueFormat)
+	}
+
+	if tc.CodeBlockThreshold < 0 {
+		return errors.New("code_block_threshold must be non-negative")
+	}
+
+	// Validate render mode; empty means the default
+	switch tc.RenderMode {
+	case "", RenderModeList, RenderModeTable, RenderModeHeadings:
+	default:
+		return fmt.Errorf("invalid render_mode: %s (valid options: list, table, headings)", tc.RenderMode)
+	}
+
+	if tc.HeadingLevel < 0 || tc.HeadingLevel > maxHeadingLevel {
+		return fmt.Errorf("invalid heading_level: %d (must be 0-%d, 0 = default)", tc.HeadingLevel, maxHeadingLevel)
+	}
+
+	switch tc.HeadingLeaves {
+	case "", RenderModeList, RenderModeTable:
+	default:
+		return fmt.Errorf("invalid heading_leaves: %s (valid options: list, table)", tc.HeadingLeaves)
+	}
+
+	if tc.CollapseDepth < 0 {
+		return errors.New("collapse_depth must be non-negative")
+	}
+	if tc.CollapseThreshold < 0 {
+		return errors.New("collapse_threshold must be non-negative")
+	}
+
+	for _, column := range tc.TableColumns {
+		if column.Header == "" {
+			return errors.New("table_columns entries must have a header")
+		}
+	}
+
 	return nil
 }
@@ -281,6 +281,64 @@ func TestValidate(t *testing.T) {
 			wantError: true,
 			errorMsg:  "indent_size must be non-negative",
 		},
+		{
+			name: "invalid render mode",
+			cfg: &TemplateConfig{
+				AttributeTemplate: "{attribute} - ({required}) {description}",
+				EscapeMode:        "inline_code",
+				IndentStyle:       "bullets",
+				RenderMode:        "grid",
+			},
+			wantError: true,
+			errorMsg:  "invalid render_mode",
+		},
+		{
+			name: "invalid column template",
+			cfg: &TemplateConfig{
+				AttributeTemplate: "{attribute} - ({required}) {description}",
+				EscapeMode:        "inline_code",
+				IndentStyle:       "bullets",
+				RenderMode:        RenderModeTable,
+				TableColumns:      []TableColumn{{Header: "Path", Template: "{{.Path"}},
+			},
+			wantError: true,
+			errorMsg:  `column "Path"`,
+		},
+		{
+			name: "heading level too deep",
+			cfg: &TemplateConfig{
+				AttributeTemplate: "{attribute} - ({required}) {description}",
+				EscapeMode:        "inline_code",
+				IndentStyle:       "bullets",
+				RenderMode:        RenderModeHeadings,
+				HeadingLevel:      7,
+			},
+			wantError: true,
+			errorMsg:  "invalid heading_level",
+		},
+		{
+			name: "invalid heading leaves",
+			cfg: &TemplateConfig{
+				AttributeTemplate: "{attribute} - ({required}) {description}",
+				EscapeMode:        "inline_code",
+				IndentStyle:       "bullets",
+				RenderMode:        RenderModeHeadings,
+				HeadingLeaves:     RenderModeHeadings,
+			},
+			wantError: true,
+			errorMsg:  "invalid heading_leaves",
+		},
+		{
+			name: "negative collapse depth",
+			cfg: &TemplateConfig{
+				AttributeTemplate: "{attribute} - ({required}) {description}",
+				EscapeMode:        "inline_code",
+				IndentStyle:       "bullets",
+				CollapseDepth:     -1,
+			},
+			wantError: true,
+			errorMsg:  "collapse_depth must be non-negative",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,224 @@
+package markdown
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/glueckkanja/marinatemd/internal/schema"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// Value formats for rendering defaults and examples.
+const (
+	ValueFormatHCL  = "hcl"
+	ValueFormatJSON = "json"
+)
+
+// valueIndent is the indentation of nested values in multi-line output.
+const valueIndent = "  "
+
+// isComposite reports whether a value is a list or object, which can span multiple lines.
+func isComposite(value any) bool {
+	switch value.(type) {
+	case []any, map[string]any, map[any]any:
+		return true
+	default:
+		return false
+	}
+}
+
+// codeSpan formats a rendered value as code: inline values are wrapped in backticks,
+// code blocks (which start on a new line) are returned as they are.
+func codeSpan(value string) string {
+	if value == "" || strings.HasPrefix(value, "\n") {
+		return value
+	}
+	return "`" + value + "`"
+}
+
+// EncodeValue renders a value decoded from HCL or YAML as a literal in the given format.
+// Single-line output separates elements with ", " (HCL: { a = 1, b = [2, 3] }); multi-line
+// output puts every element on its own line. An empty format means HCL.
+func EncodeValue(value any, format string, multiline bool) (string, error) {
+	switch format {
+	case "", ValueFormatHCL:
+		var builder strings.Builder
+		encodeHCL(&builder, value, multiline, "")
+		return builder.String(), nil
+	case ValueFormatJSON:
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if multiline {
+			encoder.SetIndent("", valueIndent)
+		}
+		if err := encoder.Encode(schema.JSONValue(value)); err != nil {
+			return "", fmt.Errorf("failed to encode value as JSON: %w", err)
+		}
+		return strings.TrimSuffix(buf.String(), "\n"), nil
+	default:
+		return "", fmt.Errorf("unknown value format: %s", format)
+	}
+}
+
+// encodeHCL writes value as an HCL literal. indent is the indentation of the line value starts on.
+func encodeHCL(builder *strings.Builder, value any, multiline bool, indent string) {
+	switch v := value.(type) {
+	case nil:
+		builder.WriteString("null")
+	case string:
+		builder.WriteString(quoteHCL(v))
+	case bool:
+		builder.WriteString(strconv.FormatBool(v))
+	case float64:
+		builder.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+	case float32:
+		builder.WriteString(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		fmt.Fprintf(builder, "%d", v)
+	case []any:
+		encodeHCLList(builder, v, multiline, indent)
+	case map[string]any:
+		encodeHCLObject(builder, v, multiline, indent)
+	case map[any]any:
+		encodeHCLObject(builder, schema.StringKeys(v), multiline, indent)
+	default:
+		builder.WriteString(quoteHCL(fmt.Sprintf("%v", v)))
+	}
+}
+
+func encodeHCLList(builder *strings.Builder, list []any, multiline bool, indent string) {
+	if len(list) == 0 {
+		builder.WriteString("[]")
+		return
+	}
+
+	builder.WriteString("[")
+	inner := indent + valueIndent
+	for i, elem := range list {
+		switch {
+		case multiline:
+			builder.WriteString("\n" + inner)
+		case i > 0:
+			builder.WriteString(", ")
+		}
+		encodeHCL(builder, elem, multiline, inner)
+		if multiline {
+			builder.WriteString(",")
+		}
+	}
+	if multiline {
+		builder.WriteString("\n" + indent)
+	}
+	builder.WriteString("]")
+}
+
+func encodeHCLObject(builder *strings.Builder, object map[string]any, multiline bool, indent string) {
+	if len(object) == 0 {
+		builder.WriteString("{}")
+		return
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	builder.WriteString("{")
+	inner := indent + valueIndent
+	if !multiline {
+		for i, key := range keys {
+			if i > 0 {
+				builder.WriteString(",")
+			}
+			builder.WriteString(" " + hclKey(key) + " = ")
+			encodeHCL(builder, object[key], false, inner)
+		}
+		builder.WriteString(" }")
+		return
+	}
+
+	// Multi-line objects align their equals signs like terraform fmt
+	names := make([]string, len(keys))
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		var value strings.Builder
+		encodeHCL(&value, object[key], true, inner)
+		names[i], values[i] = hclKey(key), value.String()
+	}
+	for i, width := range alignedWidths(names, values) {
+		builder.WriteString("\n" + inner + names[i] + strings.Repeat(" ", width-len(names[i])) + " = " + values[i])
+	}
+	builder.WriteString("\n" + indent + "}")
+}
+
+// alignedWidths returns the width to pad each name to so that the equals signs of consecutive
+// single-line values line up, as terraform fmt does. A multi-line value ends the run and is not
+// padded.
+func alignedWidths(names, values []string) []int {
+	widths := make([]int, len(names))
+	start := 0
+	for i := range names {
+		widths[i] = len(names[i])
+		if strings.Contains(values[i], "\n") {
+			start = i + 1
+			continue
+		}
+		width := 0
+		for j := start; j <= i; j++ {
+			width = max(width, len(names[j]))
+		}
+		for j := start; j <= i; j++ {
+			widths[j] = width
+		}
+	}
+	return widths
+}
+
+// hclKey returns an object key as written in HCL: bare if it is a valid identifier, quoted otherwise.
+func hclKey(key string) string {
+	if hclsyntax.ValidIdentifier(key) {
+		return key
+	}
+	return quoteHCL(key)
+}
+
+// quoteHCL quotes a string as an HCL string literal, escaping template sequences.
+func quoteHCL(s string) string {
+	var builder strings.Builder
+	builder.WriteString(`"`)
+	for i, r := range s {
+		switch r {
+		case '"':
+			builder.WriteString(`\"`)
+		case '\\':
+			builder.WriteString(`\\`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\t':
+			builder.WriteString(`\t`)
+		case '$', '%':
+			// ${ and %{ start template sequences and are escaped by doubling
+			builder.WriteRune(r)
+			if strings.HasPrefix(s[i+1:], "{") {
+				builder.WriteRune(r)
+			}
+		default:
+			if unicode.IsControl(r) {
+				fmt.Fprintf(&builder, `\u%04x`, r)
+				continue
+			}
+			builder.WriteRune(r)
+		}
+	}
+	builder.WriteString(`"`)
+	return builder.String()
+}
@@ -0,0 +1,67 @@
+package markdown //nolint:testpackage // tests need access to unexported types and constants
+
+import "testing"
+
+func TestEncodeValue(t *testing.T) {
+	value := map[string]any{
+		"name":     "web",
+		"ports":    []any{int64(80), 443.5},
+		"tls":      map[string]any{"enabled": true},
+		"my key":   nil,
+		"template": "${var.x} and %{if}",
+		"empty":    map[string]any{},
+	}
+
+	tests := []struct {
+		name      string
+		format    string
+		multiline bool
+		want      string
+	}{
+		{
+			name:   "hcl single line",
+			format: ValueFormatHCL,
+			want: `{ empty = {}, "my key" = null, name = "web", ports = [80, 443.5], ` +
+				`template = "$${var.x} and %%{if}", tls = { enabled = true } }`,
+		},
+		{
+			name:      "hcl multi line",
+			format:    "",
+			multiline: true,
+			want: `{
+  empty    = {}
+  "my key" = null
+  name     = "web"
+  ports = [
+    80,
+    443.5,
+  ]
+  template = "$${var.x} and %%{if}"
+  tls = {
+    enabled = true
+  }
+}`,
+		},
+		{
+			name:   "json single line",
+			format: ValueFormatJSON,
+			want:   `{"empty":{},"my key":null,"name":"web","ports":[80,443.5],"template":"${var.x} and %{if}","tls":{"enabled":true}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodeValue(value, tt.format, tt.multiline)
+			if err != nil {
+				t.Fatalf("EncodeValue() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("EncodeValue() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := EncodeValue("x", "yaml", false); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
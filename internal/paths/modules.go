@@ -0,0 +1,94 @@
+package paths
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/glueckkanja/marinatemd/internal/config"
+	"github.com/glueckkanja/marinatemd/internal/hclparse"
+	"github.com/glueckkanja/marinatemd/internal/logger"
+)
+
+// Module is a Terraform module found by DiscoverModules.
+type Module struct {
+	Root       string         // Absolute path to the module directory
+	Config     *config.Config // Configuration from the nearest .marinated.yml, or the fallback
+	ConfigFile string         // Config file used for this module, empty when the fallback applies
+	Err        error          // Set if the module's configuration or Terraform files could not be loaded
+}
+
+// DiscoverModules walks root and returns every directory containing MARINATED variables.
+// Each module uses the nearest .marinated.yml between its directory and root; modules without
+// one use fallback. Hidden directories (such as .git and .terraform) are skipped.
+// Directories whose configuration or Terraform files fail to load are returned with Err set,
+// so callers can report them instead of silently skipping them.
+func DiscoverModules(root string, fallback *config.Config) ([]Module, error) {
+	logger.Log.Debug("discovering modules", "root", root)
+
+	configs := make(map[string]*config.Config)
+	var modules []Module
+
+	walkErr := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != root && strings.HasPrefix(entry.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		module := Module{Root: path, Config: fallback, ConfigFile: config.FindNearest(path, root)}
+		if module.ConfigFile != "" {
+			cfg, ok := configs[module.ConfigFile]
+			if !ok {
+				var loadErr error
+				if cfg, loadErr = config.LoadFile(module.ConfigFile); loadErr != nil {
+					module.Err = loadErr
+					modules = append(modules, module)
+					return nil
+				}
+				configs[module.ConfigFile] = cfg
+			}
+			module.Config = cfg
+		}
+
+		found, findErr := hasMarinatedVariables(path, module.Config.TerraformFiles)
+		if findErr != nil {
+			module.Err = findErr
+		}
+		if found || findErr != nil {
+			logger.Log.Debug("discovered module", "path", path, "config", module.ConfigFile)
+			modules = append(modules, module)
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("failed to discover modules in %s: %w", root, walkErr)
+	}
+
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Root < modules[j].Root
+	})
+
+	return modules, nil
+}
+
+// hasMarinatedVariables reports whether any Terraform file in dir declares a MARINATED variable.
+func hasMarinatedVariables(dir string, patterns *hclparse.FilePatterns) (bool, error) {
+	parser := hclparse.NewParserWithFilePatterns(patterns)
+	if err := parser.ParseVariables(dir); err != nil {
+		return false, fmt.Errorf("failed to parse variables: %w", err)
+	}
+
+	marinatedVars, err := parser.ExtractMarinatedVars()
+	if err != nil {
+		return false, fmt.Errorf("failed to extract marinated variables: %w", err)
+	}
+
+	return len(marinatedVars) > 0, nil
+}
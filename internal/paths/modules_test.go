@@ -0,0 +1,84 @@
+package paths_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/glueckkanja/marinatemd/internal/config"
+	"github.com/glueckkanja/marinatemd/internal/paths"
+	"github.com/spf13/viper"
+)
+
+const marinatedVariable = `variable "app" {
+  description = "<!-- MARINATED: app -->"
+  type        = object({ name = string })
+}
+`
+
+const plainVariable = `variable "name" {
+  type = string
+}
+`
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestDiscoverModules(t *testing.T) {
+	viper.Reset()
+	config.SetDefaults()
+	fallback, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "variables.tf"), marinatedVariable)
+	writeFile(t, filepath.Join(root, "modules", "network", "variables.tf"), marinatedVariable)
+	writeFile(t, filepath.Join(root, "modules", "network", ".marinated.yml"), "export_path: documentation\n")
+	writeFile(t, filepath.Join(root, "modules", "plain", "variables.tf"), plainVariable)
+	writeFile(t, filepath.Join(root, "modules", "broken", "variables.tf"), "variable \"x\" {")
+	writeFile(t, filepath.Join(root, ".terraform", "modules", "remote", "variables.tf"), marinatedVariable)
+
+	modules, err := paths.DiscoverModules(root, fallback)
+	if err != nil {
+		t.Fatalf("DiscoverModules() error = %v", err)
+	}
+
+	got := make(map[string]paths.Module)
+	for _, module := range modules {
+		rel, relErr := filepath.Rel(root, module.Root)
+		if relErr != nil {
+			t.Fatalf("unexpected module path %s", module.Root)
+		}
+		got[rel] = module
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("DiscoverModules() found %d modules, want 3: %v", len(got), got)
+	}
+
+	if module := got["."]; module.Err != nil || module.Config != fallback {
+		t.Errorf("root module should use fallback config without error, got %+v", module)
+	}
+
+	network := got[filepath.Join("modules", "network")]
+	if network.Err != nil || network.Config == nil || network.Config.ExportPath != "documentation" {
+		t.Errorf("network module should use its own config, got %+v", network)
+	}
+
+	if broken := got[filepath.Join("modules", "broken")]; broken.Err == nil {
+		t.Error("broken module should be reported with an error")
+	}
+
+	if _, ok := got[filepath.Join("modules", "plain")]; ok {
+		t.Error("module without MARINATED variables should not be discovered")
+	}
+}
@@ -0,0 +1,69 @@
+package schema
+
+import "github.com/glueckkanja/marinatemd/internal/hclparse"
+
+// Constraint is a validation rule of a variable, taken from its validation blocks.
+type Constraint struct {
+	Condition    string `yaml:"condition"`
+	ErrorMessage string `yaml:"error_message,omitempty"`
+}
+
+// attachConstraints adds the variable's validations to the schema. A validation that only
+// references one attribute (var.name.path) is attached to that attribute's node;
+// all others are attached to the variable itself. Recognised value rules fill the
+// allowed_values, pattern, min and max fields of the node they refer to.
+func attachConstraints(s *Schema, validations []*hclparse.Validation) {
+	for _, validation := range validations {
+		constraint := &Constraint{
+			Condition:    validation.Condition,
+			ErrorMessage: validation.ErrorMessage,
+		}
+
+		if info := constraintTarget(s, validation.Path); info != nil && len(validation.Path) > 0 {
+			info.Constraints = append(info.Constraints, constraint)
+		} else {
+			s.Constraints = append(s.Constraints, constraint)
+		}
+
+		for _, rule := range validation.Rules {
+			if info := constraintTarget(s, rule.Path); info != nil {
+				applyValueRule(info, rule)
+			}
+		}
+	}
+}
+
+// applyValueRule records a recognised value rule on a node. Later rules for the same field win.
+func applyValueRule(info *MarinateInfo, rule *hclparse.ValueRule) {
+	if rule.AllowedValues != nil {
+		info.AllowedValues = rule.AllowedValues
+	}
+	if rule.Pattern != "" {
+		info.Pattern = rule.Pattern
+	}
+	if rule.Min != nil {
+		info.Min = rule.Min
+	}
+	if rule.Max != nil {
+		info.Max = rule.Max
+	}
+}
+
+// constraintTarget returns the metadata of the node at path, or nil if the path does not match
+// the schema (e.g. it goes through a collection). An empty path is the variable itself, which
+// only has metadata for variables that are not objects.
+func constraintTarget(s *Schema, path []string) *MarinateInfo {
+	if len(path) == 0 {
+		return s.Marinate
+	}
+
+	nodes := s.SchemaNodes
+	var node *Node
+	for _, name := range path {
+		if node = nodes[name]; node == nil {
+			return nil
+		}
+		nodes = node.Attributes
+	}
+	return node.Marinate
+}
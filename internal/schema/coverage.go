@@ -0,0 +1,73 @@
+package schema
+
+// Coverage summarises how many nodes of a schema carry a real description.
+// Nodes whose description is empty or still a TODO placeholder count as undocumented.
+// Nodes with show_description: false are intentionally undocumented and are not counted.
+// Nodes marked needs_review are listed under NeedsReview only, even when their description is
+// a placeholder, so every node appears at most once; they are counted by their description.
+type Coverage struct {
+	Variable     string   `json:"variable"`
+	Total        int      `json:"total"`
+	Documented   int      `json:"documented"`
+	Percent      float64  `json:"coverage"`
+	Undocumented []string `json:"undocumented"` // Dotted paths of undocumented nodes not marked needs_review, in render order
+	NeedsReview  []string `json:"needs_review"` // Dotted paths of nodes marked needs_review, in render order
+}
+
+// ComputeCoverage walks a schema and reports its documentation coverage.
+// Paths use the YAML keys, starting with the variable name (e.g. "app_config.database.host").
+// The root-level _marinate block of non-object variables is reported as the variable name itself.
+func ComputeCoverage(s *Schema) *Coverage {
+	c := &Coverage{Variable: s.Variable, Undocumented: []string{}, NeedsReview: []string{}}
+
+	if s.Marinate != nil {
+		c.add(s.Variable, s.Marinate)
+	}
+	c.walk(s.Variable, s.SchemaNodes)
+	c.Percent = CoveragePercent(c.Documented, c.Total)
+
+	return c
+}
+
+// CoveragePercent returns documented/total as a percentage. An empty schema is fully covered.
+func CoveragePercent(documented, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(documented) * 100 / float64(total)
+}
+
+// walk counts the nodes of a subtree in render order.
+func (c *Coverage) walk(prefix string, nodes map[string]*Node) {
+	for _, name := range SortedAttributeNames(nodes) {
+		node := nodes[name]
+		if node == nil {
+			continue
+		}
+
+		path := prefix + "." + name
+		if node.Marinate != nil {
+			c.add(path, node.Marinate)
+		}
+		c.walk(path, node.Attributes)
+	}
+}
+
+// add counts a single node.
+func (c *Coverage) add(path string, info *MarinateInfo) {
+	if info.NeedsReview {
+		c.NeedsReview = append(c.NeedsReview, path)
+	}
+	if info.ShowDescription != nil && !*info.ShowDescription {
+		return
+	}
+
+	c.Total++
+	if info.Description == "" || IsTODO(info.Description) {
+		if !info.NeedsReview {
+			c.Undocumented = append(c.Undocumented, path)
+		}
+		return
+	}
+	c.Documented++
+}
@@ -0,0 +1,42 @@
+package schema
+
+// setEffectiveDefaults records on each attribute the value it gets when the whole variable is
+// omitted: the attribute's entry in the variable-level default, or its optional(type, default)
+// when that entry is missing or null. Nested optional defaults are applied inside the resulting
+// objects, as Terraform does. Object attributes are described by their own attributes and only
+// get an effective default through them.
+//
+// Only object variables with an object default are handled; the attributes of collection
+// variables describe elements, which a variable-level default does not address by path.
+func setEffectiveDefaults(s *Schema, variableDefault any) {
+	if s.Marinate != nil {
+		return
+	}
+	if value, ok := variableDefault.(map[string]any); ok {
+		applyEffectiveDefaults(s.SchemaNodes, value)
+	}
+}
+
+func applyEffectiveDefaults(nodes map[string]*Node, value map[string]any) {
+	for name, node := range nodes {
+		if node == nil || node.Marinate == nil {
+			continue
+		}
+
+		effective := value[name]
+		if effective == nil {
+			effective = node.Marinate.Default
+		}
+		if effective == nil {
+			continue
+		}
+
+		if node.Marinate.Type != kindObject {
+			node.Marinate.EffectiveDefault = effective
+			continue
+		}
+		if object, ok := effective.(map[string]any); ok {
+			applyEffectiveDefaults(node.Attributes, object)
+		}
+	}
+}
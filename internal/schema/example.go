@@ -0,0 +1,115 @@
+package schema
+
+import "strconv"
+
+// ExampleValue builds an example value of a variable from its schema. Each attribute gets its
+// example, its default (or effective default) if it has no example, or a placeholder of its
+// type otherwise. Objects without an example are built from their attributes: all of them if
+// full is set, only the required ones otherwise. Lists, sets and maps get a single element.
+// Sensitive variables only get placeholders, unless show_sensitive_values is set.
+func ExampleValue(s *Schema, full bool) any {
+	g := &exampleGenerator{full: full, useValues: !s.MasksValues()}
+
+	switch {
+	case s.Marinate != nil:
+		return g.nodeValue(s.Variable, s.Marinate, s.SchemaNodes)
+	case len(s.SchemaNodes) > 0:
+		return g.objectValue(s.SchemaNodes)
+	case g.useValues && s.Default != nil:
+		return s.Default
+	default:
+		return nil
+	}
+}
+
+// exampleGenerator builds the example value of one variable.
+type exampleGenerator struct {
+	full      bool // Include optional attributes
+	useValues bool // Use examples and defaults; false for sensitive variables
+}
+
+func (g *exampleGenerator) nodeValue(name string, info *MarinateInfo, attrs map[string]*Node) any {
+	if info == nil {
+		return nil
+	}
+	if g.useValues && info.Example != nil {
+		return info.Example
+	}
+	// A default like {} would hide the attributes of an object, so only leaves use it
+	if g.useValues && len(attrs) == 0 {
+		if info.Default != nil {
+			return info.Default
+		}
+		if info.EffectiveDefault != nil {
+			return info.EffectiveDefault
+		}
+	}
+
+	switch info.Type {
+	case kindList, kindSet:
+		return []any{g.elementValue(name, info.ElementType, attrs)}
+	case kindMap:
+		return map[string]any{"key": g.elementValue(name, info.ValueType, attrs)}
+	default:
+		return g.elementValue(name, info.Type, attrs)
+	}
+}
+
+// elementValue returns a placeholder of the given kind: "<name>" for strings, 0 and false for
+// numbers and bools. Object and tuple structure comes from the child nodes; nested maps are
+// described by their _values child.
+func (g *exampleGenerator) elementValue(name, kind string, attrs map[string]*Node) any {
+	switch kind {
+	case kindString:
+		return "<" + name + ">"
+	case kindNumber:
+		return 0
+	case kindBool:
+		return false
+	case kindObject:
+		return g.objectValue(attrs)
+	case kindTuple:
+		return g.tupleValue(name, attrs)
+	case kindMap:
+		if values := attrs["_values"]; values != nil {
+			return g.nodeValue(name, values.Marinate, values.Attributes)
+		}
+		return map[string]any{}
+	case kindList, kindSet:
+		return []any{}
+	default:
+		return nil
+	}
+}
+
+func (g *exampleGenerator) objectValue(attrs map[string]*Node) map[string]any {
+	object := make(map[string]any, len(attrs))
+	for name, node := range attrs {
+		if node == nil || node.Marinate == nil {
+			continue
+		}
+		if g.full || node.Marinate.Required {
+			object[name] = g.nodeValue(name, node.Marinate, node.Attributes)
+		}
+	}
+	return object
+}
+
+// tupleValue returns the example of the tuple name with the positional child nodes _0, _1, ...
+// Placeholders of the positions are named after the tuple, e.g. "<pair[0]>".
+func (g *exampleGenerator) tupleValue(name string, attrs map[string]*Node) []any {
+	var elems []any
+	for _, key := range SortedAttributeNames(attrs) {
+		if _, ok := TupleElementIndex(key); !ok {
+			continue
+		}
+		node := attrs[key]
+		if node == nil {
+			elems = append(elems, nil)
+			continue
+		}
+		index, _ := TupleElementIndex(key)
+		elems = append(elems, g.nodeValue(name+"["+strconv.Itoa(index)+"]", node.Marinate, node.Attributes))
+	}
+	return elems
+}
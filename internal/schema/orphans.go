@@ -0,0 +1,144 @@
+package schema
+
+import (
+	"sort"
+	"strings"
+)
+
+// Orphan is the user documentation of an attribute that no longer exists in the HCL type.
+type Orphan struct {
+	Description    string         `yaml:"description,omitempty"`
+	KeyDescription string         `yaml:"key_description,omitempty"`
+	Example        any            `yaml:"example,omitempty"`
+	Extra          map[string]any `yaml:"extra,omitempty"`
+}
+
+// MergeReport describes documentation affected by a merge.
+// Paths are dotted and start with the variable name, as in the coverage report.
+type MergeReport struct {
+	Variable string
+
+	// Removed lists attributes that no longer exist but had user documentation, sorted.
+	Removed []string
+
+	// Reattached lists orphaned documentation that was restored because its attribute reappeared, sorted.
+	Reattached []string
+
+	// Renames lists probable attribute renames and whether they were applied.
+	Renames []Rename
+
+	// NeedsReview lists attributes newly marked needs_review because their fingerprint changed, sorted.
+	NeedsReview []string
+}
+
+// newOrphan returns the user documentation of a node, or nil if it only has generated content.
+func newOrphan(info *MarinateInfo) *Orphan {
+	if info == nil {
+		return nil
+	}
+
+	orphan := &Orphan{KeyDescription: info.KeyDescription, Example: info.Example, Extra: info.Extra}
+	if !IsTODO(info.Description) {
+		orphan.Description = info.Description
+	}
+	if orphan.Description == "" && orphan.KeyDescription == "" && orphan.Example == nil && len(orphan.Extra) == 0 {
+		return nil
+	}
+	return orphan
+}
+
+// collectOrphans records the user documentation of a removed node and all its descendants.
+func collectOrphans(path string, node *Node, removed map[string]*Orphan) {
+	if node == nil {
+		return
+	}
+	if orphan := newOrphan(node.Marinate); orphan != nil {
+		removed[path] = orphan
+	}
+	for name, child := range node.Attributes {
+		collectOrphans(path+"."+name, child, removed)
+	}
+}
+
+// resolveOrphans builds the merge report and the orphans section of the merged schema.
+// Orphans from the existing schema are kept, and newly removed documentation is added when
+// KeepOrphans is set. Orphans whose attribute exists again without a description are re-attached.
+func (b *Builder) resolveOrphans(merged *Schema, previous, removed map[string]*Orphan) *MergeReport {
+	report := &MergeReport{Variable: merged.Variable, Removed: sortedOrphanPaths(removed)}
+
+	orphans := make(map[string]*Orphan, len(previous)+len(removed))
+	for path, orphan := range previous {
+		orphans[path] = orphan
+	}
+	if b.opts.KeepOrphans {
+		for path, orphan := range removed {
+			orphans[path] = orphan
+		}
+	}
+
+	for _, path := range sortedOrphanPaths(orphans) {
+		if reattach(merged, path, orphans[path]) {
+			report.Reattached = append(report.Reattached, path)
+			delete(orphans, path)
+		}
+	}
+
+	if len(orphans) > 0 {
+		merged.Orphans = orphans
+	}
+
+	return report
+}
+
+// reattach restores orphaned documentation onto the node at path if that node exists
+// and has no description of its own.
+func reattach(s *Schema, path string, orphan *Orphan) bool {
+	info := findMarinate(s, path)
+	if info == nil || (info.Description != "" && !IsTODO(info.Description)) {
+		return false
+	}
+
+	if orphan.Description != "" {
+		info.Description = orphan.Description
+	}
+	if info.KeyDescription == "" {
+		info.KeyDescription = orphan.KeyDescription
+	}
+	if info.Example == nil {
+		info.Example = orphan.Example
+	}
+	if len(info.Extra) == 0 {
+		info.Extra = orphan.Extra
+	}
+	return true
+}
+
+// findMarinate returns the metadata of the node at a dotted path starting with the variable name.
+func findMarinate(s *Schema, path string) *MarinateInfo {
+	parts := strings.Split(path, ".")
+	if parts[0] != s.Variable {
+		return nil
+	}
+	if len(parts) == 1 {
+		return s.Marinate
+	}
+
+	nodes := s.SchemaNodes
+	var node *Node
+	for _, name := range parts[1:] {
+		if node = nodes[name]; node == nil {
+			return nil
+		}
+		nodes = node.Attributes
+	}
+	return node.Marinate
+}
+
+func sortedOrphanPaths(orphans map[string]*Orphan) []string {
+	paths := make([]string, 0, len(orphans))
+	for path := range orphans {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,185 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// minRenameSimilarity is the name similarity (0-1) two siblings need to be considered a rename.
+const minRenameSimilarity = 0.5
+
+// Rename is a probable attribute rename detected during a merge.
+type Rename struct {
+	From    string // Dotted path of the attribute in the existing schema
+	To      string // Dotted path of the attribute in the new schema
+	Applied bool   // Whether the documentation was moved to the new attribute
+}
+
+// renameCandidate is a possible pairing of a removed and an added sibling.
+type renameCandidate struct {
+	from  string
+	to    string
+	score float64
+}
+
+// detectRenames pairs documented siblings that disappeared from the existing schema with
+// siblings that are new in the new schema, when both have the same shape and similar names.
+// Every pairing is recorded in state; the applied ones are returned as new name -> old name.
+func (b *Builder) detectRenames(
+	path string,
+	newNodes, existingNodes map[string]*Node,
+	state *mergeState,
+) map[string]string {
+	var removedNames, addedNames []string
+	for _, name := range SortedAttributeNames(existingNodes) {
+		if _, ok := newNodes[name]; !ok && hasUserDocs(existingNodes[name]) {
+			removedNames = append(removedNames, name)
+		}
+	}
+	for _, name := range SortedAttributeNames(newNodes) {
+		if existing, ok := existingNodes[name]; !ok || existing == nil {
+			addedNames = append(addedNames, name)
+		}
+	}
+	if len(removedNames) == 0 || len(addedNames) == 0 {
+		return nil
+	}
+
+	var candidates []renameCandidate
+	for _, from := range removedNames {
+		for _, to := range addedNames {
+			if !sameShape(existingNodes[from], newNodes[to]) {
+				continue
+			}
+			if score := nameSimilarity(from, to); score >= minRenameSimilarity {
+				candidates = append(candidates, renameCandidate{from: from, to: to, score: score})
+			}
+		}
+	}
+	// Best matches first; the stable sort keeps name order for ties
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	applied := make(map[string]string)
+	usedFrom := make(map[string]bool)
+	usedTo := make(map[string]bool)
+	for _, candidate := range candidates {
+		if usedFrom[candidate.from] || usedTo[candidate.to] {
+			continue
+		}
+		usedFrom[candidate.from] = true
+		usedTo[candidate.to] = true
+
+		rename := Rename{From: path + "." + candidate.from, To: path + "." + candidate.to}
+		if b.opts.ConfirmRename != nil && b.opts.ConfirmRename(rename.From, rename.To) {
+			rename.Applied = true
+			applied[candidate.to] = candidate.from
+		}
+		state.renames = append(state.renames, rename)
+	}
+
+	return applied
+}
+
+// hasUserDocs reports whether a node or any of its descendants carries user documentation.
+func hasUserDocs(node *Node) bool {
+	if node == nil {
+		return false
+	}
+	if newOrphan(node.Marinate) != nil {
+		return true
+	}
+	for _, child := range node.Attributes {
+		if hasUserDocs(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameShape reports whether two nodes have the same type, default and children,
+// ignoring documentation and whether they are required.
+func sameShape(a, b *Node) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if !sameTypeInfo(a.Marinate, b.Marinate) || len(a.Attributes) != len(b.Attributes) {
+		return false
+	}
+	for name, child := range a.Attributes {
+		other, ok := b.Attributes[name]
+		if !ok || !sameShape(child, other) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameTypeInfo(a, b *MarinateInfo) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	// Defaults read from YAML and built from HCL differ in Go types (int vs int64),
+	// so they are compared by their printed form.
+	return a.Type == b.Type &&
+		a.ElementType == b.ElementType &&
+		a.ValueType == b.ValueType &&
+		fmt.Sprint(a.Default) == fmt.Sprint(b.Default)
+}
+
+// nameSimilarity scores how alike two attribute names are, from 0 to 1.
+// It is the better of the edit-distance similarity (ssl_mode -> tls_mode) and the
+// overlap of underscore-separated words (size -> disk_size).
+func nameSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	editSimilarity := 1 - float64(levenshtein(ra, rb))/float64(longest)
+
+	return max(editSimilarity, wordOverlap(a, b))
+}
+
+// wordOverlap returns the Jaccard index of the underscore-separated words of two names.
+func wordOverlap(a, b string) float64 {
+	words := make(map[string]int)
+	for _, word := range strings.Split(a, "_") {
+		words[word] |= 1
+	}
+	for _, word := range strings.Split(b, "_") {
+		words[word] |= 2
+	}
+
+	shared := 0
+	for _, seen := range words {
+		if seen == 3 {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(words))
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
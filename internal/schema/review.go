@@ -0,0 +1,52 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// fingerprintLength is the number of hex characters kept from the fingerprint hash.
+const fingerprintLength = 12
+
+// Fingerprint returns a short hash of the generator-owned fields a description is written
+// against: the type signature, the required flag and the default value.
+func Fingerprint(info *MarinateInfo) string {
+	signature := fmt.Sprintf("type=%s\nelement_type=%s\nvalue_type=%s\nrequired=%t\ndefault=%v",
+		info.Type, info.ElementType, info.ValueType, info.Required, info.Default)
+	sum := sha256.Sum256([]byte(signature))
+	return hex.EncodeToString(sum[:])[:fingerprintLength]
+}
+
+// setFingerprints stores the fingerprint of every node in a freshly built schema.
+func setFingerprints(s *Schema) {
+	if s.Marinate != nil {
+		s.Marinate.Fingerprint = Fingerprint(s.Marinate)
+	}
+	setNodeFingerprints(s.SchemaNodes)
+}
+
+func setNodeFingerprints(nodes map[string]*Node) {
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		if node.Marinate != nil {
+			node.Marinate.Fingerprint = Fingerprint(node.Marinate)
+		}
+		setNodeFingerprints(node.Attributes)
+	}
+}
+
+// descriptionStale reports whether a user-written description was written against a different
+// type signature, required flag or default than the new node has. Nodes exported before
+// fingerprints existed have no fingerprint and are never reported.
+func descriptionStale(newInfo, existingInfo *MarinateInfo) bool {
+	if newInfo == nil || existingInfo == nil || existingInfo.Fingerprint == "" {
+		return false
+	}
+	if existingInfo.Description == "" || IsTODO(existingInfo.Description) {
+		return false
+	}
+	return existingInfo.Fingerprint != newInfo.Fingerprint
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/glueckkanja/marinatemd/internal/hclparse"
@@ -17,16 +18,42 @@ var (
 )
 
 // Schema represents the internal schema model for a Terraform variable.
+// For variables whose top-level type is a collection (list, set, map or tuple),
+// Marinate describes the collection itself and SchemaNodes describe its elements.
 type Schema struct {
-	Variable    string           `yaml:"variable"`
-	Version     string           `yaml:"version"`
-	Config      *VariableConfig  `yaml:"config,omitempty"`
-	SchemaNodes map[string]*Node `yaml:"schema"`
-}
+	Variable    string             `yaml:"variable"`
+	Version     string             `yaml:"version"`
+	Config      *VariableConfig    `yaml:"config,omitempty"`
+	Sensitive   bool               `yaml:"sensitive,omitempty"`   // Variable is declared sensitive = true
+	Nullable    *bool              `yaml:"nullable,omitempty"`    // Only set for nullable = false; nil means nullable
+	Ephemeral   bool               `yaml:"ephemeral,omitempty"`   // Variable is declared ephemeral = true
+	Default     any                `yaml:"default,omitempty"`     // Variable-level default, generator-owned
+	Marinate    *MarinateInfo      `yaml:"_marinate,omitempty"`   // Root-level metadata for collection and simple-type variables
+	Constraints []*Constraint      `yaml:"constraints,omitempty"` // Validations of the whole variable, generator-owned
+	SchemaNodes map[string]*Node   `yaml:"schema"`
+	Orphans     map[string]*Orphan `yaml:"orphans,omitempty"` // Documentation of removed attributes, keyed by dotted path
+}
+
+// legacyRootNode is the node name older versions used to describe top-level collections.
+const legacyRootNode = "_root"
 
 // VariableConfig represents user-controlled settings for a variable schema.
 type VariableConfig struct {
 	Name string `yaml:"name,omitempty"`
+
+	// ShowSensitiveValues renders defaults and examples of a sensitive variable instead of masking them.
+	ShowSensitiveValues bool `yaml:"show_sensitive_values,omitempty"`
+}
+
+// IsNullable reports whether the variable accepts null, which is Terraform's default.
+func (s *Schema) IsNullable() bool {
+	return s.Nullable == nil || *s.Nullable
+}
+
+// MasksValues reports whether defaults and examples must be masked when rendering:
+// the variable is sensitive and its config does not allow showing them.
+func (s *Schema) MasksValues() bool {
+	return s.Sensitive && (s.Config == nil || !s.Config.ShowSensitiveValues)
 }
 
 // Node represents a node in the schema tree.
@@ -39,16 +66,34 @@ type Node struct {
 }
 
 // MarinateInfo contains all schema metadata for a node.
-// This includes both user-editable documentation and technical schema fields.
+// This includes both user-owned documentation and generator-owned technical schema fields.
+// On merge, user-owned fields are kept from the existing schema and generator-owned fields
+// are taken from the HCL type (see mergeMarinateInfo).
 type MarinateInfo struct {
-	Description     string `yaml:"description,omitempty"`      // User-editable description
-	ShowDescription *bool  `yaml:"show_description,omitempty"` // Control visibility of description in rendered output (nil = true by default)
-	Example         any    `yaml:"example,omitempty"`          // Example value for documentation
-	Type            string `yaml:"type,omitempty"`             // Type information (string, number, bool, object, list, map, etc.)
-	Required        bool   `yaml:"required,omitempty"`         // Whether this field is required
-	ElementType     string `yaml:"element_type,omitempty"`     // For list/set types, the element type
-	ValueType       string `yaml:"value_type,omitempty"`       // For map types, the value type
-	Default         any    `yaml:"default,omitempty"`          // Default value for optional fields
+	// User-owned fields
+	Description     string         `yaml:"description,omitempty"`      // User-editable description
+	KeyDescription  string         `yaml:"key_description,omitempty"`  // User-editable meaning of map keys (e.g. "rule name")
+	ShowDescription *bool          `yaml:"show_description,omitempty"` // Control visibility of description in rendered output (nil = true by default)
+	Example         any            `yaml:"example,omitempty"`          // Example value for documentation
+	Extra           map[string]any `yaml:"extra,omitempty"`            // Free-form team-specific metadata, never generated
+
+	// NeedsReview is set by export when the fingerprint changed under a user-written description.
+	// It is kept until the user removes it to confirm the description is still correct.
+	NeedsReview bool `yaml:"needs_review,omitempty"`
+
+	// Generator-owned fields
+	Type             string        `yaml:"type,omitempty"`              // Type information (string, number, bool, object, list, map, etc.)
+	Required         bool          `yaml:"required,omitempty"`          // Whether this field is required
+	ElementType      string        `yaml:"element_type,omitempty"`      // For list/set types, the element type
+	ValueType        string        `yaml:"value_type,omitempty"`        // For map types, the value type
+	Default          any           `yaml:"default,omitempty"`           // Default value for optional fields
+	EffectiveDefault any           `yaml:"effective_default,omitempty"` // Value when the whole variable is omitted, see setEffectiveDefaults
+	Constraints      []*Constraint `yaml:"constraints,omitempty"`       // Validations that only reference this attribute
+	AllowedValues    []any         `yaml:"allowed_values,omitempty"`    // From contains([...], var.x.path) validations
+	Pattern          string        `yaml:"pattern,omitempty"`           // From can(regex("...", var.x.path)) validations
+	Min              *float64      `yaml:"min,omitempty"`               // From var.x.path >= n validations
+	Max              *float64      `yaml:"max,omitempty"`               // From var.x.path <= n validations
+	Fingerprint      string        `yaml:"fingerprint,omitempty"`       // Hash of the type, required and default fields, see Fingerprint
 }
 
 // UnmarshalYAML implements custom YAML unmarshaling for Node.
@@ -113,7 +158,7 @@ func (n *Node) MarshalYAML() (any, error) {
 
 	// Add attributes in sorted order for deterministic output
 	if len(n.Attributes) > 0 {
-		for _, name := range sortedKeys(n.Attributes) {
+		for _, name := range SortedAttributeNames(n.Attributes) {
 			attr := n.Attributes[name]
 
 			keyNode := &yaml.Node{
@@ -133,581 +178,349 @@ func (n *Node) MarshalYAML() (any, error) {
 	return node, nil
 }
 
-// sortedKeys returns a sorted slice of keys from the Attributes map.
-// This ensures deterministic YAML output.
-func sortedKeys(attributes map[string]*Node) []string {
+// SortedAttributeNames returns a sorted slice of keys from the Attributes map.
+// Names are sorted alphabetically, except tuple element keys (_0, _1, ...) which
+// are kept in positional order. This ensures deterministic output.
+func SortedAttributeNames(attributes map[string]*Node) []string {
 	keys := make([]string, 0, len(attributes))
 	for name := range attributes {
 		keys = append(keys, name)
 	}
-	sort.Strings(keys)
+	sort.Slice(keys, func(i, j int) bool {
+		iIdx, iIsTuple := TupleElementIndex(keys[i])
+		jIdx, jIsTuple := TupleElementIndex(keys[j])
+		if iIsTuple && jIsTuple {
+			return iIdx < jIdx
+		}
+		return keys[i] < keys[j]
+	})
 	return keys
 }
 
-// Builder creates schema models from parsed HCL variables.
-type Builder struct {
+// TupleElementKey returns the attribute key of the tuple element at the given position.
+func TupleElementKey(index int) string {
+	return "_" + strconv.Itoa(index)
 }
 
-// NewBuilder creates a new schema builder.
-func NewBuilder() *Builder {
-	return &Builder{}
-}
-
-// BuildFromVariable converts an HCL variable to a Schema model.
-func (b *Builder) BuildFromVariable(variable *hclparse.Variable) (*Schema, error) {
-	schema := &Schema{
-		Variable:    variable.MarinatedID,
-		Version:     "1",
-		SchemaNodes: make(map[string]*Node),
+// TupleElementIndex reports whether key is a tuple element key and returns its position.
+func TupleElementIndex(key string) (int, bool) {
+	digits, ok := strings.CutPrefix(key, "_")
+	if !ok || digits == "" {
+		return 0, false
 	}
-
-	// Parse the type expression and build the schema tree
-	if err := b.parseType(variable.Type, schema.SchemaNodes, variable.MarinatedID); err != nil {
-		return nil, fmt.Errorf("failed to parse type for variable %s: %w", variable.Name, err)
+	index, err := strconv.Atoi(digits)
+	if err != nil || index < 0 || TupleElementKey(index) != key {
+		return 0, false
 	}
-
-	return schema, nil
+	return index, true
 }
 
-// parseType recursively parses a type expression and populates the schema nodes.
-func (b *Builder) parseType(typeExpr string, nodes map[string]*Node, contextName string) error {
-	typeExpr = strings.TrimSpace(typeExpr)
-
-	// Check for object type
-	if strings.HasPrefix(typeExpr, "object(") {
-		return b.parseObjectType(typeExpr, nodes)
-	}
-
-	// Check for optional wrapper
-	if strings.HasPrefix(typeExpr, "optional(") {
-		return b.parseOptionalType(typeExpr, nodes, contextName)
-	}
-
-	// Check for list type
-	if strings.HasPrefix(typeExpr, "list(") {
-		return b.parseListType(typeExpr, nodes, contextName)
-	}
-
-	// Check for set type
-	if strings.HasPrefix(typeExpr, "set(") {
-		return b.parseSetType(typeExpr, nodes, contextName)
-	}
-
-	// Check for map type
-	if strings.HasPrefix(typeExpr, "map(") {
-		return b.parseMapType(typeExpr, nodes, contextName)
-	}
-
-	// Simple types (string, number, bool, any)
-	// These typically don't add nodes unless explicitly needed
-	return nil
+// Builder creates schema models from parsed HCL variables.
+type Builder struct {
+	opts *BuilderOptions
 }
 
-// parseObjectType parses an object type expression.
-func (b *Builder) parseObjectType(typeExpr string, nodes map[string]*Node) error {
-	// Extract the object definition: object({...})
-	if !strings.HasPrefix(typeExpr, "object(") || !strings.HasSuffix(typeExpr, ")") {
-		return fmt.Errorf("invalid object type: %s", typeExpr)
-	}
-
-	// Extract content between object( and )
-	content := typeExpr[len("object("):]
-	content = content[:len(content)-1] // Remove trailing )
-	content = strings.TrimSpace(content)
-
-	// Remove outer braces if present
-	if strings.HasPrefix(content, "{") && strings.HasSuffix(content, "}") {
-		content = content[1 : len(content)-1]
-	}
-
-	// Parse fields
-	fields, err := b.parseObjectFields(content)
-	if err != nil {
-		return err
-	}
-
-	// Create nodes for each field
-	for fieldName, fieldType := range fields {
-		node := &Node{
-			Marinate: &MarinateInfo{
-				Description: fmt.Sprintf("# TODO: Add description for %s", fieldName),
-			},
-			Attributes: make(map[string]*Node),
-		}
-
-		// Determine if field is optional
-		isOptional := strings.HasPrefix(fieldType, "optional(")
-		node.Marinate.Required = !isOptional
-
-		// Parse the field type
-		if parseErr := b.parseFieldType(fieldType, node, fieldName); parseErr != nil {
-			return fmt.Errorf("failed to parse field %s: %w", fieldName, parseErr)
-		}
-
-		nodes[fieldName] = node
-	}
+// BuilderOptions configures optional Builder behaviour.
+type BuilderOptions struct {
+	// KeepOrphans keeps the documentation of attributes removed from the HCL type
+	// in the schema's orphans section, so it can be re-attached later.
+	KeepOrphans bool
 
-	return nil
+	// ConfirmRename decides whether a probable rename (dotted paths) is applied,
+	// moving the documentation of the old attribute to the new one.
+	// If nil, renames are only reported.
+	ConfirmRename func(from, to string) bool
 }
 
-// parseFieldType parses the type of a single field and populates the node.
-func (b *Builder) parseFieldType(typeExpr string, node *Node, _fieldName string) error {
-	typeExpr = strings.TrimSpace(typeExpr)
-
-	// Handle optional wrapper
-	if strings.HasPrefix(typeExpr, "optional(") {
-		fullArgs := extractFunctionArg(typeExpr, "optional")
-		// optional() can have a second argument (default value)
-		innerType := extractFirstArg(fullArgs)
-		// Try to extract default value (second argument)
-		defaultValue := extractSecondArg(fullArgs)
-		if defaultValue != "" {
-			node.Marinate.Default = parseDefaultValue(defaultValue)
-		}
-		return b.parseFieldType(innerType, node, _fieldName)
-	}
-
-	// Handle object type
-	if strings.HasPrefix(typeExpr, "object(") {
-		return b.parseObjectFieldType(typeExpr, node)
-	}
-
-	// Handle list type
-	if strings.HasPrefix(typeExpr, "list(") {
-		return b.parseListFieldType(typeExpr, node)
-	}
-
-	// Handle set type
-	if strings.HasPrefix(typeExpr, "set(") {
-		node.Marinate.Type = "set"
-		innerType := extractFunctionArg(typeExpr, "set")
-		node.Marinate.ElementType = b.simplifyType(innerType)
-		return nil
-	}
-
-	// Handle map type
-	if strings.HasPrefix(typeExpr, "map(") {
-		return b.parseMapFieldType(typeExpr, node)
-	}
-
-	// Simple types
-	node.Marinate.Type = typeExpr
-	return nil
+// NewBuilder creates a new schema builder.
+func NewBuilder() *Builder {
+	return NewBuilderWithOptions(nil)
 }
 
-// parseObjectFieldType parses an object type and its nested fields.
-func (b *Builder) parseObjectFieldType(typeExpr string, node *Node) error {
-	node.Marinate.Type = "object"
-	content := extractFunctionArg(typeExpr, "object")
-	if strings.HasPrefix(content, "{") && strings.HasSuffix(content, "}") {
-		content = content[1 : len(content)-1]
+// NewBuilderWithOptions creates a new schema builder with custom options.
+func NewBuilderWithOptions(opts *BuilderOptions) *Builder {
+	if opts == nil {
+		opts = &BuilderOptions{}
 	}
-	fields, err := b.parseObjectFields(content)
-	if err != nil {
-		return err
+	return &Builder{
+		opts: opts,
 	}
-	return b.populateChildNodes(node, fields)
 }
 
-// parseListFieldType parses a list type and its element type.
-func (b *Builder) parseListFieldType(typeExpr string, node *Node) error {
-	node.Marinate.Type = "list"
-	innerType := extractFunctionArg(typeExpr, "list")
-	node.Marinate.ElementType = b.simplifyType(innerType)
-	// If list contains objects, parse them as children
-	if strings.HasPrefix(innerType, "object(") {
-		return b.parseNestedObjectChildren(innerType, node)
-	}
-	return nil
-}
-
-// parseMapFieldType parses a map type and its value type.
-func (b *Builder) parseMapFieldType(typeExpr string, node *Node) error {
-	node.Marinate.Type = "map"
-	innerType := extractFunctionArg(typeExpr, "map")
-	node.Marinate.ValueType = b.simplifyType(innerType)
-	// If map contains objects, parse them as children
-	if strings.HasPrefix(innerType, "object(") {
-		return b.parseNestedObjectChildren(innerType, node)
+// BuildFromVariable converts an HCL variable to a Schema model.
+func (b *Builder) BuildFromVariable(variable *hclparse.Variable) (*Schema, error) {
+	schema := &Schema{
+		Variable:    variable.MarinatedID,
+		Version:     "1",
+		Sensitive:   variable.Sensitive,
+		Ephemeral:   variable.Ephemeral,
+		Default:     variable.Default,
+		SchemaNodes: make(map[string]*Node),
 	}
-	// If map contains another map, recurse into it via a _values child node
-	if strings.HasPrefix(innerType, "map(") {
-		return b.parseNestedMapChildren(innerType, node)
+	if !variable.Nullable {
+		nullable := false
+		schema.Nullable = &nullable
 	}
-	return nil
-}
 
-// parseNestedMapChildren creates a _values child node and recursively parses the inner map type.
-func (b *Builder) parseNestedMapChildren(mapTypeExpr string, node *Node) error {
-	valuesNode := &Node{
-		Marinate:   &MarinateInfo{},
-		Attributes: make(map[string]*Node),
+	typeExpr, comments, err := variableTypeExpr(variable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse type for variable %s: %w", variable.Name, err)
 	}
-	if err := b.parseMapFieldType(mapTypeExpr, valuesNode); err != nil {
-		return err
+	if typeExpr == nil {
+		attachConstraints(schema, variable.Validations)
+		return schema, nil
 	}
-	node.Attributes["_values"] = valuesNode
-	return nil
-}
 
-// parseNestedObjectChildren parses object fields within a list or map.
-func (b *Builder) parseNestedObjectChildren(objectTypeExpr string, node *Node) error {
-	content := extractFunctionArg(objectTypeExpr, "object")
-	if strings.HasPrefix(content, "{") && strings.HasSuffix(content, "}") {
-		content = content[1 : len(content)-1]
-	}
-	fields, err := b.parseObjectFields(content)
+	// Decode the type expression and build the schema tree
+	decoder := &typeDecoder{comments: comments}
+	typ, err := decoder.decode(typeExpr)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to parse type for variable %s: %w", variable.Name, err)
 	}
-	return b.populateChildNodes(node, fields)
-}
+	b.buildRoot(typ, !variable.HasDefault, schema)
+	setEffectiveDefaults(schema, variable.Default)
+	attachConstraints(schema, variable.Validations)
+	setFingerprints(schema)
 
-// populateChildNodes creates child nodes from parsed fields.
-func (b *Builder) populateChildNodes(node *Node, fields map[string]string) error {
-	for name, fieldType := range fields {
-		childNode := &Node{
-			Marinate: &MarinateInfo{
-				Description: fmt.Sprintf("# TODO: Add description for %s", name),
-			},
-			Attributes: make(map[string]*Node),
-		}
-		isOptional := strings.HasPrefix(fieldType, "optional(")
-		childNode.Marinate.Required = !isOptional
-		if err := b.parseFieldType(fieldType, childNode, name); err != nil {
-			return err
-		}
-		node.Attributes[name] = childNode
-	}
-	return nil
+	return schema, nil
 }
 
-// extractFirstArg extracts only the first argument from a comma-separated list.
-func extractFirstArg(args string) string {
-	depth := 0
-	for i, ch := range args {
-		switch ch {
-		case '(', '{':
-			depth++
-		case ')', '}':
-			depth--
-		case ',':
-			if depth == 0 {
-				return strings.TrimSpace(args[:i])
-			}
+// buildRoot populates the schema for a variable type.
+// Object variables expose their attributes directly as schema nodes. All other variables
+// are described by the root-level _marinate block: collection variables (list, set, map,
+// tuple) with the structure of their elements as the schema nodes, variables of a simple
+// type (string, number, bool, any) with just their type. The root is required unless the
+// variable declares a default.
+func (b *Builder) buildRoot(typ *typeConstraint, required bool, s *Schema) {
+	if typ.Kind == kindObject {
+		for _, attr := range typ.Attrs {
+			s.SchemaNodes[attr.Name] = b.buildAttributeNode(attr)
 		}
+		return
 	}
-	return strings.TrimSpace(args)
-}
 
-// simplifyType extracts the base type name from a type expression.
-func (b *Builder) simplifyType(typeExpr string) string {
-	typeExpr = strings.TrimSpace(typeExpr)
-	if strings.HasPrefix(typeExpr, "optional(") {
-		return b.simplifyType(extractFunctionArg(typeExpr, "optional"))
-	}
-	if strings.HasPrefix(typeExpr, "object(") {
-		return "object"
-	}
-	if strings.HasPrefix(typeExpr, "list(") {
-		return "list"
-	}
-	if strings.HasPrefix(typeExpr, "set(") {
-		return "set"
-	}
-	if strings.HasPrefix(typeExpr, "map(") {
-		return "map"
-	}
-	return typeExpr
+	root := newTODONode(s.Variable)
+	root.Marinate.Required = required
+	b.applyType(root, typ)
+	s.Marinate = root.Marinate
+	s.SchemaNodes = root.Attributes
 }
 
-// parseObjectFields parses the fields of an object from its body.
-func (b *Builder) parseObjectFields(content string) (map[string]string, error) {
-	fields := make(map[string]string)
-	content = strings.TrimSpace(content)
-
-	if content == "" {
-		return fields, nil
+// buildAttributeNode creates the node for a single object attribute.
+// A comment on the attribute in the HCL source seeds its description.
+func (b *Builder) buildAttributeNode(attr *typeAttribute) *Node {
+	node := newTODONode(attr.Name)
+	if attr.Comment != "" {
+		node.Marinate.Description = attr.Comment
 	}
-
-	parser := &fieldParser{
-		content: content,
-		fields:  fields,
+	node.Marinate.Required = !attr.Optional
+	if attr.HasDefault {
+		node.Marinate.Default = attr.Default
 	}
-	return parser.parse()
+	b.applyType(node, attr.Type)
+	return node
 }
 
-// fieldParser helps parse object field definitions.
-type fieldParser struct {
-	content      string
-	fields       map[string]string
-	currentField string
-	currentValue strings.Builder
-	depth        int
-	inField      bool
-}
+// applyType records the type information of typ on node and creates child nodes
+// for nested structures.
+func (b *Builder) applyType(node *Node, typ *typeConstraint) {
+	node.Marinate.Type = typ.Kind
 
-// parse processes the content and extracts field definitions.
-func (fp *fieldParser) parse() (map[string]string, error) {
-	for i := range len(fp.content) {
-		ch := fp.content[i]
-
-		switch {
-		case ch == '(' || ch == '{':
-			fp.handleOpenBracket(ch)
-		case ch == ')' || ch == '}':
-			fp.handleCloseBracket(ch)
-		case ch == '=' && fp.depth == 0 && !fp.inField:
-			fp.handleAssignment(i)
-		case fp.depth == 0 && fp.inField && (ch == '\n' || ch == '\r'):
-			fp.handleNewline(i)
-		case fp.inField:
-			fp.currentValue.WriteByte(ch)
+	switch typ.Kind {
+	case kindObject:
+		for _, attr := range typ.Attrs {
+			node.Attributes[attr.Name] = b.buildAttributeNode(attr)
+		}
+	case kindList, kindSet:
+		node.Marinate.ElementType = typ.Elem.Kind
+		// If the collection contains objects or tuples, parse them as children
+		if typ.Elem.Kind == kindObject || typ.Elem.Kind == kindTuple {
+			b.populateElementChildren(node, typ.Elem)
+		}
+	case kindMap:
+		node.Marinate.ValueType = typ.Elem.Kind
+		b.populateElementChildren(node, typ.Elem)
+	case kindTuple:
+		b.populateTupleChildren(node, typ)
+	}
+}
+
+// populateTupleChildren creates one indexed child node (_0, _1, ...) per tuple position.
+// Tuple positions are always required.
+func (b *Builder) populateTupleChildren(node *Node, typ *typeConstraint) {
+	for i, elem := range typ.Elems {
+		key := TupleElementKey(i)
+		child := newTODONode(key)
+		child.Marinate.Required = true
+		b.applyType(child, elem)
+		node.Attributes[key] = child
+	}
+}
+
+// populateElementChildren documents the element type of a collection on its node.
+// Object elements contribute their attributes as children, tuple elements their positions;
+// nested maps are described by a _values child node.
+func (b *Builder) populateElementChildren(node *Node, elem *typeConstraint) {
+	switch elem.Kind {
+	case kindObject:
+		for _, attr := range elem.Attrs {
+			node.Attributes[attr.Name] = b.buildAttributeNode(attr)
 		}
+	case kindMap:
+		valuesNode := newTODONode("_values")
+		b.applyType(valuesNode, elem)
+		node.Attributes["_values"] = valuesNode
+	case kindTuple:
+		b.populateTupleChildren(node, elem)
 	}
-
-	// Save last field
-	fp.saveCurrentField()
-	return fp.fields, nil
 }
 
-// handleOpenBracket processes opening brackets/parentheses.
-func (fp *fieldParser) handleOpenBracket(ch byte) {
-	fp.depth++
-	if fp.inField {
-		fp.currentValue.WriteByte(ch)
+// newTODONode creates a node with a TODO placeholder description.
+func newTODONode(name string) *Node {
+	return &Node{
+		Marinate: &MarinateInfo{
+			Description: fmt.Sprintf("# TODO: Add description for %s", name),
+		},
+		Attributes: make(map[string]*Node),
 	}
 }
 
-// handleCloseBracket processes closing brackets/parentheses.
-func (fp *fieldParser) handleCloseBracket(ch byte) {
-	fp.depth--
-	if fp.inField {
-		fp.currentValue.WriteByte(ch)
-	}
+// MergeWithExisting merges a new schema with an existing one.
+// Preserves user-written descriptions while updating structure.
+func (b *Builder) MergeWithExisting(newSchema, existing *Schema) (*Schema, error) {
+	merged, _, err := b.Merge(newSchema, existing)
+	return merged, err
 }
 
-// handleAssignment processes field assignment operators.
-func (fp *fieldParser) handleAssignment(pos int) {
-	// Extract field name backwards
-	j := pos - 1
-	for j >= 0 && isWhitespace(fp.content[j]) {
-		j--
-	}
-	end := j + 1
-	for j >= 0 && isIdentChar(fp.content[j]) {
-		j--
-	}
-	start := j + 1
-	fp.currentField = strings.TrimSpace(fp.content[start:end])
-	fp.inField = true
-	fp.currentValue.Reset()
-}
+// Merge merges a new schema with an existing one like MergeWithExisting and also
+// reports documentation that could not be carried over to the new schema.
+func (b *Builder) Merge(newSchema, existing *Schema) (*Schema, *MergeReport, error) {
+	existing = upgradeLegacyRoot(newSchema, existing)
+	state := &mergeState{removed: make(map[string]*Orphan)}
 
-// handleNewline processes newline characters and determines field boundaries.
-func (fp *fieldParser) handleNewline(pos int) {
-	// Check if next non-whitespace is a new field or end
-	j := pos + 1
-	for j < len(fp.content) && isWhitespace(fp.content[j]) {
-		j++
-	}
-	if j >= len(fp.content) || (j < len(fp.content) && isIdentStart(fp.content[j])) {
-		// End of this field
-		fp.saveCurrentField()
-	} else if fp.inField {
-		// Continuation
-		fp.currentValue.WriteByte(' ')
+	merged := &Schema{
+		Variable:    newSchema.Variable,
+		Version:     newSchema.Version,
+		Config:      b.mergeVariableConfig(newSchema.Config, existing.Config),
+		Sensitive:   newSchema.Sensitive,
+		Nullable:    newSchema.Nullable,
+		Ephemeral:   newSchema.Ephemeral,
+		Default:     newSchema.Default,
+		Constraints: newSchema.Constraints,
+		SchemaNodes: make(map[string]*Node),
 	}
-}
 
-// saveCurrentField saves the current field if one is being processed.
-func (fp *fieldParser) saveCurrentField() {
-	if fp.currentField != "" && fp.inField {
-		fp.fields[fp.currentField] = strings.TrimSpace(fp.currentValue.String())
-		fp.currentField = ""
-		fp.currentValue.Reset()
-		fp.inField = false
+	// Root-level metadata only exists while the variable is a collection
+	if newSchema.Marinate != nil {
+		merged.Marinate = b.mergeMarinateInfo(newSchema.Marinate, existing.Marinate)
+		state.flagStale(newSchema.Variable, merged.Marinate, newSchema.Marinate, existing.Marinate)
+	} else if orphan := newOrphan(existing.Marinate); orphan != nil {
+		state.removed[newSchema.Variable] = orphan
 	}
-}
 
-// isWhitespace returns true if ch is a whitespace character.
-func isWhitespace(ch byte) bool {
-	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
-}
-
-// isIdentChar returns true if ch can be part of an identifier.
-func isIdentChar(ch byte) bool {
-	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9') || ch == '_'
-}
+	merged.SchemaNodes = b.mergeAttributes(newSchema.Variable, newSchema.SchemaNodes, existing.SchemaNodes, state)
+	report := b.resolveOrphans(merged, existing.Orphans, state.removed)
+	report.Renames = state.renames
+	sort.Strings(state.needsReview)
+	report.NeedsReview = state.needsReview
 
-// isIdentStart returns true if ch can start an identifier.
-func isIdentStart(ch byte) bool {
-	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch == '_'
+	return merged, report, nil
 }
 
-// parseOptionalType parses an optional type wrapper.
-func (b *Builder) parseOptionalType(typeExpr string, nodes map[string]*Node, contextName string) error {
-	innerType := extractFunctionArg(typeExpr, "optional")
-	return b.parseType(innerType, nodes, contextName)
+// mergeState collects what a merge could not carry over directly.
+type mergeState struct {
+	removed     map[string]*Orphan // Documentation of removed attributes, keyed by dotted path
+	renames     []Rename           // Probable renames, in detection order
+	needsReview []string           // Attributes whose description became stale in this merge
 }
 
-// parseListType parses a list type.
-func (b *Builder) parseListType(typeExpr string, nodes map[string]*Node, contextName string) error {
-	node := &Node{
-		Marinate: &MarinateInfo{
-			Description: fmt.Sprintf("# TODO: Add description for %s", contextName),
-			Type:        "list",
-			Required:    true,
-		},
-		Attributes: make(map[string]*Node),
+// flagStale marks merged as needing review if its description was written against a
+// different fingerprint than the new node has.
+func (s *mergeState) flagStale(path string, merged, newInfo, existingInfo *MarinateInfo) {
+	if merged == nil || !descriptionStale(newInfo, existingInfo) {
+		return
 	}
-
-	innerType := extractFunctionArg(typeExpr, "list")
-	node.Marinate.ElementType = b.simplifyType(innerType)
-
-	nodes["_root"] = node
-	return nil
+	merged.NeedsReview = true
+	s.needsReview = append(s.needsReview, path)
 }
 
-// parseSetType parses a set type.
-func (b *Builder) parseSetType(typeExpr string, nodes map[string]*Node, contextName string) error {
-	node := &Node{
-		Marinate: &MarinateInfo{
-			Description: fmt.Sprintf("# TODO: Add description for %s", contextName),
-			Type:        "set",
-			Required:    true,
-		},
-		Attributes: make(map[string]*Node),
-	}
-
-	innerType := extractFunctionArg(typeExpr, "set")
-	node.Marinate.ElementType = b.simplifyType(innerType)
-
-	nodes["_root"] = node
-	return nil
-}
+// mergeAttributes merges sibling nodes, preserving descriptions from existing.
+// Existing nodes that no longer exist are matched against new siblings to detect renames;
+// the rest are recorded as removed if they carry user documentation.
+func (b *Builder) mergeAttributes(
+	path string,
+	newNodes, existingNodes map[string]*Node,
+	state *mergeState,
+) map[string]*Node {
+	merged := make(map[string]*Node, len(newNodes))
+	renamedFrom := b.detectRenames(path, newNodes, existingNodes, state)
 
-// parseMapType parses a map type.
-func (b *Builder) parseMapType(typeExpr string, nodes map[string]*Node, contextName string) error {
-	node := &Node{
-		Marinate: &MarinateInfo{
-			Description: fmt.Sprintf("# TODO: Add description for %s", contextName),
-			Type:        "map",
-			Required:    true,
-		},
-		Attributes: make(map[string]*Node),
-	}
-
-	innerType := extractFunctionArg(typeExpr, "map")
-	node.Marinate.ValueType = b.simplifyType(innerType)
-
-	// If map contains objects, parse them
-	if strings.HasPrefix(innerType, "object(") {
-		content := extractFunctionArg(innerType, "object")
-		if strings.HasPrefix(content, "{") && strings.HasSuffix(content, "}") {
-			content = content[1 : len(content)-1]
-		}
-		fields, err := b.parseObjectFields(content)
-		if err != nil {
-			return err
+	for nodeName, newNode := range newNodes {
+		existingName := nodeName
+		if from, ok := renamedFrom[nodeName]; ok {
+			existingName = from
 		}
-		for name, fieldType := range fields {
-			childNode := &Node{
-				Marinate: &MarinateInfo{
-					Description: fmt.Sprintf("# TODO: Add description for %s", name),
-				},
-				Attributes: make(map[string]*Node),
-			}
-			isOptional := strings.HasPrefix(fieldType, "optional(")
-			childNode.Marinate.Required = !isOptional
-			if parseErr5 := b.parseFieldType(fieldType, childNode, name); parseErr5 != nil {
-				return parseErr5
-			}
-			node.Attributes[name] = childNode
+
+		if existingNode, ok := existingNodes[existingName]; ok && existingNode != nil {
+			// Node exists in both (possibly under its old name) - merge them
+			merged[nodeName] = b.mergeNodes(path+"."+nodeName, newNode, existingNode, state)
+		} else {
+			// New node - add it
+			merged[nodeName] = newNode
 		}
 	}
 
-	// If map contains another map, recurse into it via a _values child node
-	if strings.HasPrefix(innerType, "map(") {
-		if err := b.parseNestedMapChildren(innerType, node); err != nil {
-			return err
+	renamed := make(map[string]bool, len(renamedFrom))
+	for _, from := range renamedFrom {
+		renamed[from] = true
+	}
+	for nodeName, existingNode := range existingNodes {
+		if _, ok := newNodes[nodeName]; !ok && !renamed[nodeName] {
+			collectOrphans(path+"."+nodeName, existingNode, state.removed)
 		}
 	}
 
-	nodes["_root"] = node
-	return nil
+	return merged
 }
 
-// extractFunctionArg extracts the argument(s) from a function call.
-// For "optional(...)" it returns "...".
-func extractFunctionArg(expr, funcName string) string {
-	if !strings.HasPrefix(expr, funcName+"(") {
-		return ""
-	}
-	content := expr[len(funcName)+1:]
-	// Find matching closing paren
-	depth := 1
-	for i, ch := range content {
-		switch ch {
-		case '(':
-			depth++
-		case ')':
-			depth--
-			if depth == 0 {
-				return strings.TrimSpace(content[:i])
-			}
-		}
+// upgradeLegacyRoot converts an existing schema that describes a top-level collection
+// through a _root node into the root-level _marinate form, so that its descriptions
+// survive the merge. Schemas that don't need upgrading are returned unchanged.
+func upgradeLegacyRoot(newSchema, existing *Schema) *Schema {
+	if newSchema.Marinate == nil || existing.Marinate != nil {
+		return existing
 	}
-	return strings.TrimSpace(content)
-}
-
-// MergeWithExisting merges a new schema with an existing one.
-// Preserves user-written descriptions while updating structure.
-func (b *Builder) MergeWithExisting(newSchema, existing *Schema) (*Schema, error) {
-	merged := &Schema{
-		Variable:    newSchema.Variable,
-		Version:     newSchema.Version,
-		Config:      b.mergeVariableConfig(newSchema.Config, existing.Config),
-		SchemaNodes: make(map[string]*Node),
+	root, ok := existing.SchemaNodes[legacyRootNode]
+	if !ok || root == nil || len(existing.SchemaNodes) != 1 {
+		return existing
 	}
 
-	// Merge each node from new schema
-	for nodeName, newNode := range newSchema.SchemaNodes {
-		if existingNode, ok := existing.SchemaNodes[nodeName]; ok {
-			// Node exists in both - merge them
-			merged.SchemaNodes[nodeName] = b.mergeNodes(newNode, existingNode)
-		} else {
-			// New node - add it
-			merged.SchemaNodes[nodeName] = newNode
-		}
+	return &Schema{
+		Variable:    existing.Variable,
+		Version:     existing.Version,
+		Config:      existing.Config,
+		Marinate:    root.Marinate,
+		SchemaNodes: root.Attributes,
 	}
-
-	return merged, nil
 }
 
 // mergeNodes merges two nodes, preserving descriptions from existing.
-func (b *Builder) mergeNodes(newNode, existingNode *Node) *Node {
-	merged := &Node{
-		Attributes: make(map[string]*Node),
-	}
+func (b *Builder) mergeNodes(path string, newNode, existingNode *Node, state *mergeState) *Node {
+	merged := &Node{}
 
 	// Merge Marinate metadata
 	merged.Marinate = b.mergeMarinateInfo(newNode.Marinate, existingNode.Marinate)
+	state.flagStale(path, merged.Marinate, newNode.Marinate, existingNode.Marinate)
 
 	// Merge attributes
-	for attrName, newAttr := range newNode.Attributes {
-		if existingAttr, ok := existingNode.Attributes[attrName]; ok {
-			merged.Attributes[attrName] = b.mergeNodes(newAttr, existingAttr)
-		} else {
-			merged.Attributes[attrName] = newAttr
-		}
-	}
+	merged.Attributes = b.mergeAttributes(path, newNode.Attributes, existingNode.Attributes, state)
 
 	return merged
 }
 
 // mergeMarinateInfo merges Marinate metadata from new and existing nodes.
-// Preserves user-written descriptions if they're not TODO placeholders.
+// Generator-owned fields always come from the new node. User-owned fields are kept from
+// the existing node, except for TODO placeholder descriptions which are regenerated.
 func (b *Builder) mergeMarinateInfo(newInfo, existingInfo *MarinateInfo) *MarinateInfo {
 	if newInfo == nil && existingInfo == nil {
 		return nil
@@ -724,9 +537,16 @@ func (b *Builder) mergeMarinateInfo(newInfo, existingInfo *MarinateInfo) *Marina
 		merged.ElementType = newInfo.ElementType
 		merged.ValueType = newInfo.ValueType
 		merged.Default = newInfo.Default
+		merged.EffectiveDefault = newInfo.EffectiveDefault
+		merged.Constraints = newInfo.Constraints
+		merged.AllowedValues = newInfo.AllowedValues
+		merged.Pattern = newInfo.Pattern
+		merged.Min = newInfo.Min
+		merged.Max = newInfo.Max
+		merged.Fingerprint = newInfo.Fingerprint
 	}
 
-	// Preserve existing user-written descriptions if they're not TODO placeholders
+	// Preserve existing user-owned fields; descriptions only if they're not TODO placeholders
 	if existingInfo != nil {
 		if existingInfo.Description != "" && !b.isTODO(existingInfo.Description) {
 			merged.Description = existingInfo.Description
@@ -734,6 +554,10 @@ func (b *Builder) mergeMarinateInfo(newInfo, existingInfo *MarinateInfo) *Marina
 		if existingInfo.Example != nil {
 			merged.Example = existingInfo.Example
 		}
+		merged.KeyDescription = existingInfo.KeyDescription
+		merged.ShowDescription = existingInfo.ShowDescription
+		merged.Extra = existingInfo.Extra
+		merged.NeedsReview = existingInfo.NeedsReview
 	}
 
 	return merged
@@ -741,161 +565,15 @@ func (b *Builder) mergeMarinateInfo(newInfo, existingInfo *MarinateInfo) *Marina
 
 // isTODO checks if a description is a TODO placeholder.
 func (b *Builder) isTODO(desc string) bool {
-	re := regexp.MustCompile(`(?i)#\s*TODO`)
-	return re.MatchString(desc)
-}
-
-// extractSecondArg extracts the second argument from a comma-separated list.
-// Returns empty string if there's no second argument.
-func extractSecondArg(args string) string {
-	depth := 0
-	for i, ch := range args {
-		switch ch {
-		case '(', '{', '[':
-			depth++
-		case ')', '}', ']':
-			depth--
-		case ',':
-			if depth == 0 {
-				// Found the comma, return everything after it (trimmed)
-				secondArg := strings.TrimSpace(args[i+1:])
-				return secondArg
-			}
-		}
-	}
-	return ""
-}
-
-// parseDefaultValue converts a default value string from HCL to a Go value.
-// This handles strings, numbers, bools, lists, and maps.
-func parseDefaultValue(defaultStr string) any {
-	defaultStr = strings.TrimSpace(defaultStr)
-
-	// Handle null
-	if defaultStr == "null" {
-		return nil
-	}
-
-	// Handle boolean
-	if defaultStr == "true" {
-		return true
-	}
-	if defaultStr == "false" {
-		return false
-	}
-
-	// Handle strings (quoted)
-	if strings.HasPrefix(defaultStr, "\"") && strings.HasSuffix(defaultStr, "\"") {
-		// Unescape the string
-		unquoted := defaultStr[1 : len(defaultStr)-1]
-		return unquoted
-	}
-
-	// Handle empty list
-	if defaultStr == "[]" {
-		return []any{}
-	}
-
-	// Handle list
-	if strings.HasPrefix(defaultStr, "[") && strings.HasSuffix(defaultStr, "]") {
-		return parseListDefault(defaultStr)
-	}
-
-	// Handle empty map/object
-	if defaultStr == "{}" {
-		return map[string]any{}
-	}
-
-	// Handle map/object
-	if strings.HasPrefix(defaultStr, "{") && strings.HasSuffix(defaultStr, "}") {
-		return parseMapDefault(defaultStr)
-	}
-
-	// Try to parse as number
-	// For simplicity, we'll return the string as-is since YAML can handle numeric strings
-	// This avoids complex float/int parsing
-	return defaultStr
-}
-
-// parseListDefault parses a list default value like ["a", "b"] or [1, 2].
-func parseListDefault(listStr string) []any {
-	listStr = strings.TrimSpace(listStr)
-	if listStr == "[]" {
-		return []any{}
-	}
-
-	// Remove brackets
-	content := listStr[1 : len(listStr)-1]
-	content = strings.TrimSpace(content)
-
-	if content == "" {
-		return []any{}
-	}
-
-	// Split by comma, respecting nested structures
-	items := splitByComma(content)
-	result := make([]any, 0, len(items))
-
-	for _, item := range items {
-		item = strings.TrimSpace(item)
-		result = append(result, parseDefaultValue(item))
-	}
-
-	return result
-}
-
-// parseMapDefault parses a map/object default value.
-func parseMapDefault(mapStr string) map[string]any {
-	mapStr = strings.TrimSpace(mapStr)
-	if mapStr == "{}" {
-		return map[string]any{}
-	}
-
-	// Remove braces
-	content := mapStr[1 : len(mapStr)-1]
-	content = strings.TrimSpace(content)
-
-	if content == "" {
-		return map[string]any{}
-	}
-
-	// For now, return a simple representation
-	// Full parsing would require more complex logic
-	return map[string]any{}
+	return IsTODO(desc)
 }
 
-// splitByComma splits a string by commas, respecting nested brackets.
-func splitByComma(s string) []string {
-	var result []string
-	var current strings.Builder
-	depth := 0
-
-	for _, ch := range s {
-		switch ch {
-		case '(', '{', '[':
-			depth++
-			current.WriteRune(ch)
-		case ')', '}', ']':
-			depth--
-			current.WriteRune(ch)
-		case ',':
-			if depth == 0 {
-				result = append(result, current.String())
-				current.Reset()
-			} else {
-				current.WriteRune(ch)
-			}
-		default:
-			current.WriteRune(ch)
-		}
-	}
-
-	// Add the last item
-	if current.Len() > 0 {
-		result = append(result, current.String())
-	}
+// todoPattern matches the TODO placeholders generated for undocumented nodes.
+var todoPattern = regexp.MustCompile(`(?i)#\s*TODO`)
 
-	return result
+// IsTODO reports whether a description is a TODO placeholder.
+func IsTODO(desc string) bool {
+	return todoPattern.MatchString(desc)
 }
 
 func (b *Builder) mergeVariableConfig(newCfg, existingCfg *VariableConfig) *VariableConfig {
@@ -913,7 +591,11 @@ func (b *Builder) mergeVariableConfig(newCfg, existingCfg *VariableConfig) *Vari
 		merged.Name = newCfg.Name
 	}
 
-	if merged.Name == "" {
+	if existingCfg != nil {
+		merged.ShowSensitiveValues = existingCfg.ShowSensitiveValues
+	}
+
+	if *merged == (VariableConfig{}) {
 		return nil
 	}
 
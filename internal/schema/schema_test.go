@@ -1,10 +1,13 @@
 package schema_test
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/glueckkanja/marinatemd/internal/hclparse"
 	"github.com/glueckkanja/marinatemd/internal/schema"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestBuildFromHCL_SimpleTypes(t *testing.T) {
@@ -580,20 +583,19 @@ func TestBuildFromHCL_NestedMap(t *testing.T) {
 		t.Fatalf("BuildFromVariable() error = %v", err)
 	}
 
-	root, ok := s.SchemaNodes["_root"]
-	if !ok {
-		t.Fatal("expected '_root' node")
+	if s.Marinate == nil {
+		t.Fatal("expected root-level _marinate")
 	}
-	if root.Marinate.Type != "map" {
-		t.Errorf("_root type = %v, want map", root.Marinate.Type)
+	if s.Marinate.Type != "map" {
+		t.Errorf("root type = %v, want map", s.Marinate.Type)
 	}
-	if root.Marinate.ValueType != "map" {
-		t.Errorf("_root value_type = %v, want map", root.Marinate.ValueType)
+	if s.Marinate.ValueType != "map" {
+		t.Errorf("root value_type = %v, want map", s.Marinate.ValueType)
 	}
 
-	values, ok := root.Attributes["_values"]
+	values, ok := s.SchemaNodes["_values"]
 	if !ok {
-		t.Fatal("expected '_values' child node on _root")
+		t.Fatal("expected '_values' schema node")
 	}
 	if values.Marinate.Type != "map" {
 		t.Errorf("_values type = %v, want map", values.Marinate.Type)
@@ -613,3 +615,999 @@ func TestBuildFromHCL_NestedMap(t *testing.T) {
 		t.Error("expected name to be required")
 	}
 }
+
+// TestBuildFromHCL_CommentsAndTrailingCommas tests that type expressions with comments,
+// trailing commas and unusual whitespace are decoded correctly.
+func TestBuildFromHCL_CommentsAndTrailingCommas(t *testing.T) {
+	t.Parallel()
+
+	variable := &hclparse.Variable{
+		Name: "app_config",
+		Type: `object({
+    # The database settings
+    database = optional(object({
+      host = string # hostname, e.g. "db(1)"
+      port = optional(number, 5432),
+      /* ssl = bool */
+      tags    =    optional( list( string ) , ["a", "b"] ),
+    })),
+    name = string, // trailing comma
+  })`,
+		Description: "<!-- MARINATED: app_config -->",
+		MarinatedID: "app_config",
+	}
+
+	b := schema.NewBuilder()
+	s, err := b.BuildFromVariable(variable)
+	if err != nil {
+		t.Fatalf("BuildFromVariable() error = %v", err)
+	}
+
+	if len(s.SchemaNodes) != 2 {
+		t.Fatalf("expected 2 top-level nodes, got %d", len(s.SchemaNodes))
+	}
+
+	database := s.SchemaNodes["database"]
+	if database == nil || len(database.Attributes) != 3 {
+		t.Fatalf("expected database with 3 attributes, got %+v", database)
+	}
+	if _, ok := database.Attributes["ssl"]; ok {
+		t.Error("expected commented-out attribute 'ssl' to be ignored")
+	}
+
+	port := database.Attributes["port"]
+	if port.Marinate.Type != "number" || port.Marinate.Required {
+		t.Errorf("unexpected port metadata: %+v", port.Marinate)
+	}
+	if port.Marinate.Default != int64(5432) {
+		t.Errorf("port default = %#v, want 5432", port.Marinate.Default)
+	}
+
+	tags := database.Attributes["tags"]
+	if tags.Marinate.Type != "list" || tags.Marinate.ElementType != "string" {
+		t.Errorf("unexpected tags metadata: %+v", tags.Marinate)
+	}
+	if defaults, ok := tags.Marinate.Default.([]any); !ok || len(defaults) != 2 {
+		t.Errorf("tags default = %#v, want [a b]", tags.Marinate.Default)
+	}
+}
+
+// TestBuildFromHCL_InvalidTypeReportsRange tests that decoding errors include the source range.
+func TestBuildFromHCL_InvalidTypeReportsRange(t *testing.T) {
+	t.Parallel()
+
+	variable := &hclparse.Variable{
+		Name: "app_config",
+		Type: `object({
+    host = strin
+  })`,
+		MarinatedID: "app_config",
+	}
+
+	b := schema.NewBuilder()
+	_, err := b.BuildFromVariable(variable)
+	if err == nil {
+		t.Fatal("expected error for unknown type keyword")
+	}
+	if !strings.Contains(err.Error(), "app_config.type:2,12-17") {
+		t.Errorf("expected error to contain source range, got %v", err)
+	}
+}
+
+// TestBuildFromHCL_Tuple tests that tuple positions are modelled as indexed child nodes.
+func TestBuildFromHCL_Tuple(t *testing.T) {
+	t.Parallel()
+
+	variable := &hclparse.Variable{
+		Name: "settings",
+		Type: `object({
+    range = tuple([string, number, object({
+      name = string
+      port = optional(number)
+    })])
+  })`,
+		Description: "<!-- MARINATED: settings -->",
+		MarinatedID: "settings",
+	}
+
+	b := schema.NewBuilder()
+	s, err := b.BuildFromVariable(variable)
+	if err != nil {
+		t.Fatalf("BuildFromVariable() error = %v", err)
+	}
+
+	rng, ok := s.SchemaNodes["range"]
+	if !ok {
+		t.Fatal("expected 'range' node")
+	}
+	if rng.Marinate.Type != "tuple" {
+		t.Errorf("range type = %v, want tuple", rng.Marinate.Type)
+	}
+	if len(rng.Attributes) != 3 {
+		t.Fatalf("expected 3 tuple positions, got %d", len(rng.Attributes))
+	}
+
+	wantTypes := []string{"string", "number", "object"}
+	for i, want := range wantTypes {
+		pos, exists := rng.Attributes[schema.TupleElementKey(i)]
+		if !exists {
+			t.Fatalf("expected tuple position %d", i)
+		}
+		if pos.Marinate.Type != want {
+			t.Errorf("position %d type = %v, want %v", i, pos.Marinate.Type, want)
+		}
+		if !pos.Marinate.Required {
+			t.Errorf("expected position %d to be required", i)
+		}
+	}
+
+	obj := rng.Attributes["_2"]
+	if _, exists := obj.Attributes["port"]; !exists {
+		t.Error("expected object fields on tuple position _2")
+	}
+}
+
+func TestSortedAttributeNames_TuplePositions(t *testing.T) {
+	t.Parallel()
+
+	attrs := map[string]*schema.Node{}
+	for i := range 12 {
+		attrs[schema.TupleElementKey(i)] = &schema.Node{}
+	}
+
+	got := schema.SortedAttributeNames(attrs)
+	for i, name := range got {
+		if name != schema.TupleElementKey(i) {
+			t.Fatalf("position %d = %s, want %s (got %v)", i, name, schema.TupleElementKey(i), got)
+		}
+	}
+}
+
+// TestBuildFromHCL_TopLevelCollectionOfObjects tests that top-level collections are described
+// by the root-level _marinate block with the element attributes as schema nodes.
+func TestBuildFromHCL_TopLevelCollectionOfObjects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		typeExpr   string
+		hasDefault bool
+		wantType   string
+		wantElem   string
+	}{
+		{name: "list", typeExpr: `list(object({ name = string }))`, wantType: "list", wantElem: "object"},
+		{name: "set", typeExpr: `set(object({ name = string }))`, wantType: "set", wantElem: "object"},
+		{name: "map", typeExpr: `map(object({ name = string }))`, hasDefault: true, wantType: "map", wantElem: "object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			variable := &hclparse.Variable{
+				Name:        "rules",
+				Type:        tt.typeExpr,
+				HasDefault:  tt.hasDefault,
+				MarinatedID: "rules",
+			}
+
+			s, err := schema.NewBuilder().BuildFromVariable(variable)
+			if err != nil {
+				t.Fatalf("BuildFromVariable() error = %v", err)
+			}
+
+			if s.Marinate == nil {
+				t.Fatal("expected root-level _marinate")
+			}
+			if s.Marinate.Type != tt.wantType {
+				t.Errorf("root type = %v, want %v", s.Marinate.Type, tt.wantType)
+			}
+			elem := s.Marinate.ElementType
+			if tt.wantType == "map" {
+				elem = s.Marinate.ValueType
+			}
+			if elem != tt.wantElem {
+				t.Errorf("root element type = %v, want %v", elem, tt.wantElem)
+			}
+			if s.Marinate.Required == tt.hasDefault {
+				t.Errorf("root required = %v, want %v", s.Marinate.Required, !tt.hasDefault)
+			}
+			if s.Marinate.Description != "# TODO: Add description for rules" {
+				t.Errorf("unexpected root description %q", s.Marinate.Description)
+			}
+			if _, ok := s.SchemaNodes["name"]; !ok {
+				t.Error("expected element attribute 'name' as schema node")
+			}
+		})
+	}
+}
+
+func TestMergeWithExisting_UpgradesLegacyRootNode(t *testing.T) {
+	t.Parallel()
+
+	existing := &schema.Schema{
+		Variable: "rules",
+		Version:  "1",
+		SchemaNodes: map[string]*schema.Node{
+			"_root": {
+				Marinate: &schema.MarinateInfo{
+					Description: "Firewall rules",
+					Type:        "map",
+					ValueType:   "object",
+				},
+				Attributes: map[string]*schema.Node{
+					"name": {
+						Marinate:   &schema.MarinateInfo{Description: "The rule name"},
+						Attributes: map[string]*schema.Node{},
+					},
+				},
+			},
+		},
+	}
+
+	newSchema, err := schema.NewBuilder().BuildFromVariable(&hclparse.Variable{
+		Name:        "rules",
+		Type:        `map(object({ name = string }))`,
+		MarinatedID: "rules",
+	})
+	if err != nil {
+		t.Fatalf("BuildFromVariable() error = %v", err)
+	}
+
+	existing.SchemaNodes["_root"].Marinate.KeyDescription = "rule name"
+
+	merged, err := schema.NewBuilder().MergeWithExisting(newSchema, existing)
+	if err != nil {
+		t.Fatalf("MergeWithExisting() error = %v", err)
+	}
+
+	if _, ok := merged.SchemaNodes["_root"]; ok {
+		t.Error("expected legacy _root node to be removed")
+	}
+	if merged.Marinate == nil || merged.Marinate.Description != "Firewall rules" {
+		t.Errorf("expected root description to be preserved, got %+v", merged.Marinate)
+	}
+	if merged.Marinate.KeyDescription != "rule name" {
+		t.Errorf("expected key description to be preserved, got %q", merged.Marinate.KeyDescription)
+	}
+	if merged.SchemaNodes["name"].Marinate.Description != "The rule name" {
+		t.Errorf("expected element description to be preserved, got %q",
+			merged.SchemaNodes["name"].Marinate.Description)
+	}
+}
+
+// TestBuilder_Merge_PreservesUserOwnedFields tests that every user-owned _marinate field
+// survives a merge while generator-owned fields follow the new HCL type.
+func TestBuilder_Merge_PreservesUserOwnedFields(t *testing.T) {
+	t.Parallel()
+
+	hidden := false
+	existing := &schema.Schema{
+		Variable: "app",
+		Version:  "1",
+		SchemaNodes: map[string]*schema.Node{
+			"port": {
+				Marinate: &schema.MarinateInfo{
+					Description:     "Listening port",
+					KeyDescription:  "unused",
+					ShowDescription: &hidden,
+					Example:         8080,
+					Extra:           map[string]any{"owner": "team-web"},
+					Type:            "string",
+					Required:        true,
+				},
+				Attributes: map[string]*schema.Node{},
+			},
+		},
+	}
+
+	newSchema, err := schema.NewBuilder().BuildFromVariable(&hclparse.Variable{
+		Name:        "app",
+		Type:        `object({ port = optional(number, 80) })`,
+		MarinatedID: "app",
+	})
+	if err != nil {
+		t.Fatalf("BuildFromVariable() error = %v", err)
+	}
+
+	merged, err := schema.NewBuilder().MergeWithExisting(newSchema, existing)
+	if err != nil {
+		t.Fatalf("MergeWithExisting() error = %v", err)
+	}
+
+	info := merged.SchemaNodes["port"].Marinate
+	if info.Description != "Listening port" || info.KeyDescription != "unused" || info.Example != 8080 {
+		t.Errorf("expected documentation to be preserved, got %+v", info)
+	}
+	if info.ShowDescription == nil || *info.ShowDescription {
+		t.Errorf("expected show_description: false to be preserved, got %v", info.ShowDescription)
+	}
+	if info.Extra["owner"] != "team-web" {
+		t.Errorf("expected extra to be preserved, got %v", info.Extra)
+	}
+	if info.Type != "number" || info.Required {
+		t.Errorf("expected generator-owned fields from the HCL type, got type=%q required=%v", info.Type, info.Required)
+	}
+}
+
+// TestBuilder_Merge_MarksStaleDescriptions tests that a changed type signature marks
+// user-written descriptions as needs_review, and that the flag sticks until removed.
+func TestBuilder_Merge_MarksStaleDescriptions(t *testing.T) {
+	t.Parallel()
+
+	build := func(typ string) *schema.Schema {
+		s, err := schema.NewBuilder().BuildFromVariable(&hclparse.Variable{
+			Name:        "app",
+			Type:        typ,
+			MarinatedID: "app",
+		})
+		if err != nil {
+			t.Fatalf("BuildFromVariable() error = %v", err)
+		}
+		return s
+	}
+
+	existing := build(`object({ name = string, tags = string, port = number })`)
+	existing.SchemaNodes["name"].Marinate.Description = "Application name"
+	existing.SchemaNodes["tags"].Marinate.Description = "Comma-separated tags"
+	if existing.SchemaNodes["tags"].Marinate.Fingerprint == "" {
+		t.Fatal("expected built nodes to have a fingerprint")
+	}
+
+	// tags changes type; port changes too but only has a TODO placeholder
+	merged, report, err := schema.NewBuilder().Merge(
+		build(`object({ name = string, tags = list(string), port = optional(number, 80) })`), existing)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	if !merged.SchemaNodes["tags"].Marinate.NeedsReview {
+		t.Error("expected tags to need review after its type changed")
+	}
+	if merged.SchemaNodes["name"].Marinate.NeedsReview || merged.SchemaNodes["port"].Marinate.NeedsReview {
+		t.Error("expected only documented attributes with a changed fingerprint to need review")
+	}
+	if len(report.NeedsReview) != 1 || report.NeedsReview[0] != "app.tags" {
+		t.Errorf("report.NeedsReview = %v, want [app.tags]", report.NeedsReview)
+	}
+
+	// The flag is kept on the next export, but not reported again
+	merged, report, err = schema.NewBuilder().Merge(
+		build(`object({ name = string, tags = list(string), port = optional(number, 80) })`), merged)
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if !merged.SchemaNodes["tags"].Marinate.NeedsReview {
+		t.Error("expected needs_review to be kept until removed by the user")
+	}
+	if len(report.NeedsReview) != 0 {
+		t.Errorf("report.NeedsReview = %v, want none", report.NeedsReview)
+	}
+}
+
+// TestBuildFromVariable_AttachesConstraints tests that validations referencing a single
+// attribute are attached to it and all others to the variable.
+func TestBuildFromVariable_AttachesConstraints(t *testing.T) {
+	t.Parallel()
+
+	s, err := schema.NewBuilder().BuildFromVariable(&hclparse.Variable{
+		Name:        "app",
+		Type:        `object({ database = object({ port = number }), name = string })`,
+		MarinatedID: "app",
+		Validations: []*hclparse.Validation{
+			{Condition: "var.app.database.port > 0", ErrorMessage: "Port must be positive.", Path: []string{"database", "port"}},
+			{Condition: "var.app.name != var.app.database.host", ErrorMessage: "Name and host must differ."},
+			{Condition: "var.app.cache.size > 0", Path: []string{"cache", "size"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildFromVariable() error = %v", err)
+	}
+
+	port := s.SchemaNodes["database"].Attributes["port"].Marinate
+	if len(port.Constraints) != 1 || port.Constraints[0].ErrorMessage != "Port must be positive." {
+		t.Errorf("expected the port constraint on database.port, got %+v", port.Constraints)
+	}
+
+	// Unknown paths fall back to the variable
+	if len(s.Constraints) != 2 {
+		t.Fatalf("expected 2 variable constraints, got %d", len(s.Constraints))
+	}
+	if s.Constraints[0].Condition != "var.app.name != var.app.database.host" ||
+		s.Constraints[1].Condition != "var.app.cache.size > 0" {
+		t.Errorf("unexpected variable constraints: %+v, %+v", s.Constraints[0], s.Constraints[1])
+	}
+}
+
+// TestBuildFromVariable_AppliesValueRules tests that recognised validation idioms fill
+// the allowed_values, pattern, min and max fields of the referenced attributes.
+func TestBuildFromVariable_AppliesValueRules(t *testing.T) {
+	t.Parallel()
+
+	low, high := 1.0, 10.0
+	s, err := schema.NewBuilder().BuildFromVariable(&hclparse.Variable{
+		Name:        "app",
+		Type:        `object({ mode = string, count = number })`,
+		MarinatedID: "app",
+		Validations: []*hclparse.Validation{
+			{
+				Condition: `contains(["dev", "prod"], var.app.mode) && var.app.count >= 1 && var.app.count <= 10`,
+				Rules: []*hclparse.ValueRule{
+					{Path: []string{"mode"}, AllowedValues: []any{"dev", "prod"}},
+					{Path: []string{"count"}, Min: &low, Max: &high},
+					{Path: []string{"missing"}, Pattern: "^x$"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildFromVariable() error = %v", err)
+	}
+
+	mode := s.SchemaNodes["mode"].Marinate
+	if len(mode.AllowedValues) != 2 || mode.AllowedValues[0] != "dev" {
+		t.Errorf("expected allowed values on mode, got %v", mode.AllowedValues)
+	}
+	count := s.SchemaNodes["count"].Marinate
+	if count.Min == nil || *count.Min != 1 || count.Max == nil || *count.Max != 10 {
+		t.Errorf("expected range 1..10 on count, got min=%v max=%v", count.Min, count.Max)
+	}
+	if len(s.Constraints) != 1 {
+		t.Errorf("expected the validation to stay a variable constraint, got %d", len(s.Constraints))
+	}
+}
+
+// TestBuildFromVariable_EffectiveDefaults tests that the variable-level default is merged with
+// nested optional defaults into the value each attribute gets when the variable is omitted.
+func TestBuildFromVariable_EffectiveDefaults(t *testing.T) {
+	t.Parallel()
+
+	s, err := schema.NewBuilder().BuildFromVariable(&hclparse.Variable{
+		Name: "app",
+		Type: `object({
+			name = string
+			port = optional(number, 8080)
+			tls = optional(object({
+				enabled = optional(bool, true)
+				cert    = optional(string)
+			}), {})
+			proxy = optional(object({
+				host = optional(string, "localhost")
+			}))
+		})`,
+		MarinatedID: "app",
+		Default: map[string]any{
+			"name": "web",
+			"tls":  map[string]any{"cert": "x.pem"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildFromVariable() error = %v", err)
+	}
+
+	if s.Default == nil {
+		t.Error("expected the variable-level default to be recorded")
+	}
+
+	tests := []struct {
+		path []string
+		want any
+	}{
+		{[]string{"name"}, "web"},
+		{[]string{"port"}, int64(8080)},
+		{[]string{"tls", "cert"}, "x.pem"},
+		{[]string{"tls", "enabled"}, true},
+		{[]string{"tls"}, nil},           // Objects are described by their attributes
+		{[]string{"proxy", "host"}, nil}, // The proxy object itself defaults to null
+	}
+	for _, tt := range tests {
+		node := s.SchemaNodes[tt.path[0]]
+		for _, name := range tt.path[1:] {
+			node = node.Attributes[name]
+		}
+		if got := node.Marinate.EffectiveDefault; got != tt.want {
+			t.Errorf("%s: effective default = %#v, want %#v", strings.Join(tt.path, "."), got, tt.want)
+		}
+	}
+}
+
+// TestValidateValues tests that examples and defaults are type-checked against their node's type.
+func TestValidateValues(t *testing.T) {
+	t.Parallel()
+
+	s, err := schema.NewBuilder().BuildFromVariable(&hclparse.Variable{
+		Name: "app",
+		Type: `object({
+			port  = number
+			tags  = optional(list(string), [])
+			rules = optional(map(object({ priority = number, enabled = optional(bool) })))
+			tls   = object({ enabled = bool })
+		})`,
+		MarinatedID: "app",
+		Default:     map[string]any{"port": 80, "tls": map[string]any{"enabled": true, "mode": "strict"}},
+	})
+	if err != nil {
+		t.Fatalf("BuildFromVariable() error = %v", err)
+	}
+
+	s.SchemaNodes["port"].Marinate.Example = "abc"
+	s.SchemaNodes["tags"].Marinate.Example = []any{"a", "b"}
+	s.SchemaNodes["rules"].Marinate.Example = map[string]any{
+		"web": map[string]any{"priority": 100, "enable": true},
+	}
+	s.SchemaNodes["tls"].Marinate.Example = map[string]any{}
+
+	var got []string
+	for _, valueErr := range schema.ValidateValues(s) {
+		got = append(got, valueErr.Error())
+	}
+	want := []string{
+		`app: default: tls.mode: unexpected attribute "mode"`,
+		"app.port: example: a number is required",
+		`app.rules: example: ["web"].enable: unexpected attribute "enable"`,
+		`app.tls: example: attribute "enabled" is required`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("ValidateValues() =\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// TestCheckValue tests that every problem in a tfvars value is reported, not just the first.
+func TestCheckValue(t *testing.T) {
+	t.Parallel()
+
+	s, err := schema.NewBuilder().BuildFromVariable(&hclparse.Variable{
+		Name: "database",
+		Type: `object({
+			name     = string
+			ssl_mode = optional(string, "require")
+			replicas = optional(list(object({ zone = number })), [])
+		})`,
+		MarinatedID: "database",
+	})
+	if err != nil {
+		t.Fatalf("BuildFromVariable() error = %v", err)
+	}
+
+	val := cty.ObjectVal(map[string]cty.Value{
+		"ssl_mod": cty.StringVal("require"),
+		"replicas": cty.TupleVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{"zone": cty.StringVal("a")}),
+			cty.ObjectVal(map[string]cty.Value{"zone": cty.NumberIntVal(2)}),
+		}),
+	})
+
+	var got []string
+	for _, problem := range schema.CheckValue(s, val) {
+		got = append(got, problem.Error())
+	}
+	want := []string{
+		"replicas[0].zone: a number is required",
+		`ssl_mod: unexpected attribute "ssl_mod"`,
+		`attribute "name" is required`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("CheckValue() =\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// TestExampleValue tests that examples are preferred over defaults and placeholders, and that
+// minimal examples only contain required attributes.
+func TestExampleValue(t *testing.T) {
+	t.Parallel()
+
+	s, err := schema.NewBuilder().BuildFromVariable(&hclparse.Variable{
+		Name: "app",
+		Type: `object({
+			name  = string
+			port  = optional(number, 8080)
+			rules = list(object({ priority = number, enabled = optional(bool) }))
+			tags  = optional(map(string))
+		})`,
+		MarinatedID: "app",
+	})
+	if err != nil {
+		t.Fatalf("BuildFromVariable() error = %v", err)
+	}
+	s.SchemaNodes["name"].Marinate.Example = "web"
+
+	minimal := schema.ExampleValue(s, false)
+	wantMinimal := map[string]any{
+		"name":  "web",
+		"rules": []any{map[string]any{"priority": 0}},
+	}
+	if !reflect.DeepEqual(minimal, wantMinimal) {
+		t.Errorf("ExampleValue(minimal) = %#v, want %#v", minimal, wantMinimal)
+	}
+
+	full := schema.ExampleValue(s, true)
+	wantFull := map[string]any{
+		"name":  "web",
+		"port":  s.SchemaNodes["port"].Marinate.Default,
+		"rules": []any{map[string]any{"priority": 0, "enabled": false}},
+		"tags":  map[string]any{"key": "<tags>"},
+	}
+	if !reflect.DeepEqual(full, wantFull) {
+		t.Errorf("ExampleValue(full) = %#v, want %#v", full, wantFull)
+	}
+
+	// Simple-type variables and tuple positions get placeholders named after the variable
+	for typ, want := range map[string]any{
+		"string":                "<region>",
+		"number":                0,
+		"tuple([string, bool])": []any{"<region[0]>", false},
+	} {
+		s, err := schema.NewBuilder().BuildFromVariable(&hclparse.Variable{Name: "region", Type: typ, MarinatedID: "region"})
+		if err != nil {
+			t.Fatalf("BuildFromVariable(%s) error = %v", typ, err)
+		}
+		if got := schema.ExampleValue(s, false); !reflect.DeepEqual(got, want) {
+			t.Errorf("ExampleValue(%s) = %#v, want %#v", typ, got, want)
+		}
+	}
+}
+
+// TestBuilder_Merge_VariableAttributes tests that sensitive, nullable and ephemeral follow
+// the HCL declaration while show_sensitive_values is kept from the existing schema.
+func TestBuilder_Merge_VariableAttributes(t *testing.T) {
+	t.Parallel()
+
+	newSchema, err := schema.NewBuilder().BuildFromVariable(&hclparse.Variable{
+		Name:        "db",
+		Type:        `object({ password = optional(string, "changeme") })`,
+		MarinatedID: "db",
+		Sensitive:   true,
+		Nullable:    false,
+	})
+	if err != nil {
+		t.Fatalf("BuildFromVariable() error = %v", err)
+	}
+	if !newSchema.Sensitive || newSchema.IsNullable() || newSchema.Ephemeral {
+		t.Errorf("unexpected attributes: sensitive=%v nullable=%v ephemeral=%v",
+			newSchema.Sensitive, newSchema.IsNullable(), newSchema.Ephemeral)
+	}
+	if !newSchema.MasksValues() {
+		t.Error("expected values of a sensitive variable to be masked")
+	}
+
+	existing := &schema.Schema{
+		Variable:    "db",
+		Version:     "1",
+		Config:      &schema.VariableConfig{ShowSensitiveValues: true},
+		SchemaNodes: map[string]*schema.Node{},
+	}
+	merged, err := schema.NewBuilder().MergeWithExisting(newSchema, existing)
+	if err != nil {
+		t.Fatalf("MergeWithExisting() error = %v", err)
+	}
+	if merged.Config == nil || !merged.Config.ShowSensitiveValues {
+		t.Errorf("expected show_sensitive_values to be preserved, got %+v", merged.Config)
+	}
+	if !merged.Sensitive || merged.IsNullable() || merged.MasksValues() {
+		t.Errorf("unexpected merged attributes: sensitive=%v nullable=%v masked=%v",
+			merged.Sensitive, merged.IsNullable(), merged.MasksValues())
+	}
+}
+
+// TestBuildFromHCL_CommentsSeedDescriptions tests that comments on object attributes
+// become the initial descriptions, and that existing YAML descriptions take precedence.
+func TestBuildFromHCL_CommentsSeedDescriptions(t *testing.T) {
+	t.Parallel()
+
+	variable := &hclparse.Variable{
+		Name: "app_config",
+		Type: `object({
+    # Database settings
+    database = object({
+      host = string # DB hostname
+      port = number
+    })
+  })`,
+		MarinatedID: "app_config",
+	}
+
+	b := schema.NewBuilder()
+	s, err := b.BuildFromVariable(variable)
+	if err != nil {
+		t.Fatalf("BuildFromVariable() error = %v", err)
+	}
+
+	database := s.SchemaNodes["database"]
+	if database.Marinate.Description != "Database settings" {
+		t.Errorf("database description = %q, want leading comment", database.Marinate.Description)
+	}
+	if got := database.Attributes["host"].Marinate.Description; got != "DB hostname" {
+		t.Errorf("host description = %q, want inline comment", got)
+	}
+	if got := database.Attributes["port"].Marinate.Description; got != "# TODO: Add description for port" {
+		t.Errorf("port description = %q, want TODO placeholder", got)
+	}
+
+	existing := &schema.Schema{
+		Variable: "app_config",
+		Version:  "1",
+		SchemaNodes: map[string]*schema.Node{
+			"database": {
+				Marinate: &schema.MarinateInfo{Description: "# TODO: Add description for database"},
+				Attributes: map[string]*schema.Node{
+					"host": {
+						Marinate:   &schema.MarinateInfo{Description: "Hand-written hostname"},
+						Attributes: map[string]*schema.Node{},
+					},
+				},
+			},
+		},
+	}
+
+	merged, err := b.MergeWithExisting(s, existing)
+	if err != nil {
+		t.Fatalf("MergeWithExisting() error = %v", err)
+	}
+	if got := merged.SchemaNodes["database"].Marinate.Description; got != "Database settings" {
+		t.Errorf("expected comment to replace TODO placeholder, got %q", got)
+	}
+	if got := merged.SchemaNodes["database"].Attributes["host"].Marinate.Description; got != "Hand-written hostname" {
+		t.Errorf("expected YAML description to win over comment, got %q", got)
+	}
+}
+
+// TestBuildFromHCL_TrailingComments tests that comments after the closing bracket of a
+// multi-line attribute are kept, and that attributes sharing a line don't share its comment.
+func TestBuildFromHCL_TrailingComments(t *testing.T) {
+	t.Parallel()
+
+	variable := &hclparse.Variable{
+		Name: "app_config",
+		Type: `object({
+    # Database settings
+    database = optional(object({
+      host = string
+    })) # Required in production
+    zones = list(
+      string
+    ) # Availability zones
+    name = string, size = number # Instance size
+  })`,
+		MarinatedID: "app_config",
+	}
+
+	s, err := schema.NewBuilder().BuildFromVariable(variable)
+	if err != nil {
+		t.Fatalf("BuildFromVariable() error = %v", err)
+	}
+
+	want := map[string]string{
+		"database": "Database settings Required in production",
+		"zones":    "Availability zones",
+		"name":     "# TODO: Add description for name",
+		"size":     "Instance size",
+	}
+	for name, description := range want {
+		if got := s.SchemaNodes[name].Marinate.Description; got != description {
+			t.Errorf("%s description = %q, want %q", name, got, description)
+		}
+	}
+	if got := s.SchemaNodes["database"].Attributes["host"].Marinate.Description; got != "# TODO: Add description for host" {
+		t.Errorf("host description = %q, want TODO placeholder", got)
+	}
+}
+
+func TestComputeCoverage(t *testing.T) {
+	hidden := false
+	s := &schema.Schema{
+		Variable: "app_config",
+		Marinate: &schema.MarinateInfo{Description: "Applications keyed by name"},
+		SchemaNodes: map[string]*schema.Node{
+			"name": {Marinate: &schema.MarinateInfo{Description: "# TODO: Add description for name"}},
+			"database": {
+				Marinate: &schema.MarinateInfo{Description: "Database settings"},
+				Attributes: map[string]*schema.Node{
+					"host":     {Marinate: &schema.MarinateInfo{Description: ""}},
+					"port":     {Marinate: &schema.MarinateInfo{Description: "Port", NeedsReview: true}},
+					"internal": {Marinate: &schema.MarinateInfo{ShowDescription: &hidden}},
+				},
+			},
+			"tags": {Marinate: &schema.MarinateInfo{Description: "# TODO: Add description for tags", NeedsReview: true}},
+		},
+	}
+
+	c := schema.ComputeCoverage(s)
+
+	if c.Total != 6 || c.Documented != 3 {
+		t.Errorf("ComputeCoverage() = %d/%d, want 3/6", c.Documented, c.Total)
+	}
+	if c.Percent != 50 {
+		t.Errorf("Percent = %v, want 50", c.Percent)
+	}
+
+	// Nodes marked needs_review are only listed as such
+	want := []string{"app_config.database.host", "app_config.name"}
+	if strings.Join(c.Undocumented, ",") != strings.Join(want, ",") {
+		t.Errorf("Undocumented = %v, want %v", c.Undocumented, want)
+	}
+	want = []string{"app_config.database.port", "app_config.tags"}
+	if strings.Join(c.NeedsReview, ",") != strings.Join(want, ",") {
+		t.Errorf("NeedsReview = %v, want %v", c.NeedsReview, want)
+	}
+
+	if empty := schema.ComputeCoverage(&schema.Schema{Variable: "empty"}); empty.Percent != 100 {
+		t.Errorf("empty schema coverage = %v, want 100", empty.Percent)
+	}
+}
+
+// TestComputeCoverage_NestedMapValues tests that the _values node of a nested map gets a TODO
+// placeholder like every other generated node, and that documenting it completes the coverage.
+func TestComputeCoverage_NestedMapValues(t *testing.T) {
+	s, err := schema.NewBuilder().BuildFromVariable(&hclparse.Variable{
+		Name:        "zones",
+		Type:        "map(map(string))",
+		MarinatedID: "zones",
+	})
+	if err != nil {
+		t.Fatalf("BuildFromVariable() error = %v", err)
+	}
+
+	values := s.SchemaNodes["_values"]
+	if values == nil || !schema.IsTODO(values.Marinate.Description) {
+		t.Fatalf("expected a TODO placeholder on _values, got %+v", values)
+	}
+
+	s.Marinate.Description = "DNS zones"
+	values.Marinate.Description = "Records of the zone"
+	if c := schema.ComputeCoverage(s); c.Percent != 100 {
+		t.Errorf("Percent = %v, want 100 (undocumented: %v)", c.Percent, c.Undocumented)
+	}
+}
+
+func TestBuilder_Merge_ReportsRemovedAttributes(t *testing.T) {
+	existing := &schema.Schema{
+		Variable: "db",
+		Version:  "1",
+		SchemaNodes: map[string]*schema.Node{
+			"host": {Marinate: &schema.MarinateInfo{Description: "Database host", Type: "string"}},
+			"ssl_mode": {
+				Marinate: &schema.MarinateInfo{Description: "TLS mode", Type: "string"},
+			},
+			"legacy": {
+				Marinate: &schema.MarinateInfo{Description: "# TODO: Add description for legacy"},
+				Attributes: map[string]*schema.Node{
+					"flag": {Marinate: &schema.MarinateInfo{Example: true}},
+				},
+			},
+			"unused": {Marinate: &schema.MarinateInfo{Description: "# TODO: Add description for unused"}},
+		},
+	}
+	newSchema := &schema.Schema{
+		Variable: "db",
+		Version:  "1",
+		SchemaNodes: map[string]*schema.Node{
+			"host": {Marinate: &schema.MarinateInfo{Description: "# TODO: Add description for host", Type: "string"}},
+		},
+	}
+
+	t.Run("report only", func(t *testing.T) {
+		merged, report, err := schema.NewBuilder().Merge(newSchema, existing)
+		if err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+
+		want := []string{"db.legacy.flag", "db.ssl_mode"}
+		if strings.Join(report.Removed, ",") != strings.Join(want, ",") {
+			t.Errorf("Removed = %v, want %v", report.Removed, want)
+		}
+		if merged.Orphans != nil {
+			t.Errorf("Orphans = %v, want none without KeepOrphans", merged.Orphans)
+		}
+	})
+
+	t.Run("keep and re-attach", func(t *testing.T) {
+		builder := schema.NewBuilderWithOptions(&schema.BuilderOptions{KeepOrphans: true})
+		merged, _, err := builder.Merge(newSchema, existing)
+		if err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+
+		orphan := merged.Orphans["db.ssl_mode"]
+		if orphan == nil || orphan.Description != "TLS mode" {
+			t.Fatalf("Orphans[db.ssl_mode] = %+v, want TLS mode", orphan)
+		}
+		if _, ok := merged.Orphans["db.unused"]; ok {
+			t.Error("TODO-only attribute should not become an orphan")
+		}
+
+		// The attribute comes back: its description is restored and the orphan is dropped
+		readded := &schema.Schema{
+			Variable: "db",
+			Version:  "1",
+			SchemaNodes: map[string]*schema.Node{
+				"host":     {Marinate: &schema.MarinateInfo{Description: "# TODO: Add description for host"}},
+				"ssl_mode": {Marinate: &schema.MarinateInfo{Description: "# TODO: Add description for ssl_mode"}},
+			},
+		}
+		again, report, err := builder.Merge(readded, merged)
+		if err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+
+		if got := again.SchemaNodes["ssl_mode"].Marinate.Description; got != "TLS mode" {
+			t.Errorf("ssl_mode description = %q, want re-attached %q", got, "TLS mode")
+		}
+		if len(report.Reattached) != 1 || report.Reattached[0] != "db.ssl_mode" {
+			t.Errorf("Reattached = %v, want [db.ssl_mode]", report.Reattached)
+		}
+		if _, ok := again.Orphans["db.ssl_mode"]; ok {
+			t.Error("re-attached orphan should be removed from the orphans section")
+		}
+		if again.Orphans["db.legacy.flag"] == nil {
+			t.Error("orphans that were not re-attached should be kept")
+		}
+	})
+}
+
+func TestBuilder_Merge_DetectsRenames(t *testing.T) {
+	existing := &schema.Schema{
+		Variable: "db",
+		Version:  "1",
+		SchemaNodes: map[string]*schema.Node{
+			"ssl_mode": {Marinate: &schema.MarinateInfo{Description: "TLS mode", Type: "string", Default: "require"}},
+			"port":     {Marinate: &schema.MarinateInfo{Description: "Port", Type: "number"}},
+		},
+	}
+	newSchema := func() *schema.Schema {
+		return &schema.Schema{
+			Variable: "db",
+			Version:  "1",
+			SchemaNodes: map[string]*schema.Node{
+				"tls_mode": {Marinate: &schema.MarinateInfo{Description: "# TODO: Add description for tls_mode", Type: "string", Default: "require"}},
+				// Similar name but different type: not a rename
+				"ports": {Marinate: &schema.MarinateInfo{Description: "# TODO: Add description for ports", Type: "list", ElementType: "number"}},
+			},
+		}
+	}
+
+	t.Run("accepted", func(t *testing.T) {
+		var asked []string
+		builder := schema.NewBuilderWithOptions(&schema.BuilderOptions{
+			ConfirmRename: func(from, to string) bool {
+				asked = append(asked, from+"->"+to)
+				return true
+			},
+		})
+
+		merged, report, err := builder.Merge(newSchema(), existing)
+		if err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+
+		if len(asked) != 1 || asked[0] != "db.ssl_mode->db.tls_mode" {
+			t.Errorf("ConfirmRename calls = %v, want [db.ssl_mode->db.tls_mode]", asked)
+		}
+		if got := merged.SchemaNodes["tls_mode"].Marinate.Description; got != "TLS mode" {
+			t.Errorf("tls_mode description = %q, want %q", got, "TLS mode")
+		}
+		if len(report.Renames) != 1 || !report.Renames[0].Applied {
+			t.Errorf("Renames = %+v, want one applied rename", report.Renames)
+		}
+		if len(report.Removed) != 1 || report.Removed[0] != "db.port" {
+			t.Errorf("Removed = %v, want [db.port]", report.Removed)
+		}
+	})
+
+	t.Run("report only", func(t *testing.T) {
+		merged, report, err := schema.NewBuilder().Merge(newSchema(), existing)
+		if err != nil {
+			t.Fatalf("Merge() error = %v", err)
+		}
+
+		if !schema.IsTODO(merged.SchemaNodes["tls_mode"].Marinate.Description) {
+			t.Error("description should not be moved without confirmation")
+		}
+		want := schema.Rename{From: "db.ssl_mode", To: "db.tls_mode", Applied: false}
+		if len(report.Renames) != 1 || report.Renames[0] != want {
+			t.Errorf("Renames = %+v, want [%+v]", report.Renames, want)
+		}
+		if strings.Join(report.Removed, ",") != "db.port,db.ssl_mode" {
+			t.Errorf("Removed = %v, want [db.port db.ssl_mode]", report.Removed)
+		}
+	})
+}
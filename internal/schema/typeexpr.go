@@ -0,0 +1,255 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/glueckkanja/marinatemd/internal/hclparse"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// Type constraint kinds recognised by the decoder.
+const (
+	kindString = "string"
+	kindNumber = "number"
+	kindBool   = "bool"
+	kindAny    = "any"
+	kindObject = "object"
+	kindList   = "list"
+	kindSet    = "set"
+	kindMap    = "map"
+	kindTuple  = "tuple"
+)
+
+// typeConstraint is a decoded Terraform type constraint expression.
+// It mirrors the structure of the HCL AST so the builder never has to look at raw source text.
+type typeConstraint struct {
+	Kind  string            // Primitive keyword or collection/structural kind
+	Elem  *typeConstraint   // Element type for list, set and map
+	Elems []*typeConstraint // Positional element types for tuple
+	Attrs []*typeAttribute  // Object attributes in declaration order
+	Range hcl.Range         // Source range of the expression, used in error messages
+}
+
+// typeAttribute is a single attribute of an object type constraint.
+type typeAttribute struct {
+	Name       string
+	Type       *typeConstraint
+	Optional   bool   // Wrapped in optional(...)
+	HasDefault bool   // optional(...) has a second argument
+	Default    any    // Decoded default value (only set when HasDefault is true)
+	Comment    string // Comment attached to the attribute in the HCL source
+	Range      hcl.Range
+}
+
+// typeDecoder decodes type constraint expressions of a single variable.
+type typeDecoder struct {
+	comments map[int]string // Source comments keyed by line (see hclparse.ExtractComments)
+}
+
+// variableTypeExpr returns the type expression of a variable and the comments inside it.
+// Variables produced by hclparse.Parser carry the parsed expression; for variables constructed
+// by hand only the source string is available, so it is parsed here.
+// Returns a nil expression if the variable has no type constraint.
+func variableTypeExpr(variable *hclparse.Variable) (hcl.Expression, map[int]string, error) {
+	if variable.TypeExpr != nil {
+		return variable.TypeExpr, variable.TypeComments, nil
+	}
+	if variable.Type == "" {
+		return nil, nil, nil
+	}
+
+	src := []byte(variable.Type)
+	filename := variable.Name + ".type"
+	expr, diags := hclsyntax.ParseExpression(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, nil, fmt.Errorf("failed to parse type expression: %w", diags)
+	}
+	return expr, hclparse.ExtractComments(src, filename, hcl.InitialPos), nil
+}
+
+// decode decodes an HCL type constraint expression.
+// It follows the same rules as Terraform's typeexpr package but keeps attribute
+// order, optional defaults and source ranges.
+func (d *typeDecoder) decode(expr hcl.Expression) (*typeConstraint, error) {
+	// Primitive keywords are plain traversals: string, number, bool, any
+	if traversal, diags := hcl.AbsTraversalForExpr(expr); !diags.HasErrors() {
+		if len(traversal) != 1 {
+			return nil, fmt.Errorf("%s: invalid type specification", expr.Range())
+		}
+		switch name := traversal.RootName(); name {
+		case kindString, kindNumber, kindBool, kindAny:
+			return &typeConstraint{Kind: name, Range: expr.Range()}, nil
+		case kindList, kindSet, kindMap, kindObject, kindTuple:
+			return nil, fmt.Errorf("%s: the %s type constructor requires one argument", expr.Range(), name)
+		default:
+			return nil, fmt.Errorf("%s: unknown type keyword %q", expr.Range(), name)
+		}
+	}
+
+	call, diags := hcl.ExprCall(expr)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("%s: a type specification is either a primitive type keyword or a type constructor call", expr.Range())
+	}
+
+	switch call.Name {
+	case kindList, kindSet, kindMap:
+		if len(call.Arguments) != 1 {
+			return nil, fmt.Errorf("%s: the %s type constructor requires one argument", call.ArgsRange, call.Name)
+		}
+		elem, err := d.decode(call.Arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		return &typeConstraint{Kind: call.Name, Elem: elem, Range: expr.Range()}, nil
+
+	case kindObject:
+		if len(call.Arguments) != 1 {
+			return nil, fmt.Errorf("%s: the object type constructor requires one argument", call.ArgsRange)
+		}
+		attrs, err := d.decodeObjectAttributes(call.Arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		return &typeConstraint{Kind: kindObject, Attrs: attrs, Range: expr.Range()}, nil
+
+	case kindTuple:
+		if len(call.Arguments) != 1 {
+			return nil, fmt.Errorf("%s: the tuple type constructor requires one argument", call.ArgsRange)
+		}
+		elems, err := d.decodeTupleElements(call.Arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		return &typeConstraint{Kind: kindTuple, Elems: elems, Range: expr.Range()}, nil
+
+	case "optional":
+		// optional() is only meaningful on object attributes; elsewhere it is unwrapped.
+		if len(call.Arguments) == 0 {
+			return nil, fmt.Errorf("%s: optional requires a type argument", call.ArgsRange)
+		}
+		return d.decode(call.Arguments[0])
+
+	default:
+		return nil, fmt.Errorf("%s: unknown type constructor %q", expr.Range(), call.Name)
+	}
+}
+
+// decodeObjectAttributes decodes the attribute map of an object(...) constructor.
+func (d *typeDecoder) decodeObjectAttributes(expr hcl.Expression) ([]*typeAttribute, error) {
+	pairs, diags := hcl.ExprMap(expr)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("%s: object type constructor requires a map whose keys are attribute names", expr.Range())
+	}
+
+	attrs := make([]*typeAttribute, 0, len(pairs))
+	for _, pair := range pairs {
+		name := hcl.ExprAsKeyword(pair.Key)
+		if name == "" {
+			return nil, fmt.Errorf("%s: object constructor map keys must be attribute names", pair.Key.Range())
+		}
+
+		attr, err := d.decodeObjectAttribute(name, pair.Value)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, attr)
+	}
+	d.attachComments(pairs, attrs)
+
+	return attrs, nil
+}
+
+// attachComments sets the comments of object attributes from the comments on their key line
+// and, for values spanning several lines, on the line that closes the value:
+//
+//	# Database settings
+//	database = object({
+//	  host = string
+//	}) # Required in production
+//
+// A comment on a line where attributes end belongs to the last of them, so attributes sharing
+// a line don't all get its comment. A comment on any other key line belongs to the first
+// attribute starting there.
+func (d *typeDecoder) attachComments(pairs []hcl.KeyValuePair, attrs []*typeAttribute) {
+	owners := make(map[int]int, len(pairs)) // Attribute index by the line of the comment it gets
+	for i, pair := range pairs {
+		owners[pair.Value.Range().End.Line] = i
+	}
+	for i, pair := range pairs {
+		if _, ok := owners[pair.Key.Range().Start.Line]; !ok {
+			owners[pair.Key.Range().Start.Line] = i
+		}
+	}
+
+	for i, pair := range pairs {
+		var parts []string
+		keyLine, endLine := pair.Key.Range().Start.Line, pair.Value.Range().End.Line
+		if comment := d.comments[keyLine]; comment != "" && owners[keyLine] == i {
+			parts = append(parts, comment)
+		}
+		if comment := d.comments[endLine]; comment != "" && endLine != keyLine && owners[endLine] == i {
+			parts = append(parts, comment)
+		}
+		attrs[i].Comment = strings.Join(parts, " ")
+	}
+}
+
+// decodeTupleElements decodes the element list of a tuple(...) constructor.
+func (d *typeDecoder) decodeTupleElements(expr hcl.Expression) ([]*typeConstraint, error) {
+	elemExprs, diags := hcl.ExprList(expr)
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("%s: tuple type constructor requires a list of element types", expr.Range())
+	}
+
+	elems := make([]*typeConstraint, 0, len(elemExprs))
+	for _, elemExpr := range elemExprs {
+		elem, err := d.decode(elemExpr)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, elem)
+	}
+
+	return elems, nil
+}
+
+// decodeObjectAttribute decodes the type of a single object attribute,
+// handling the optional(type, default) modifier.
+func (d *typeDecoder) decodeObjectAttribute(name string, expr hcl.Expression) (*typeAttribute, error) {
+	attr := &typeAttribute{Name: name, Range: expr.Range()}
+
+	call, diags := hcl.ExprCall(expr)
+	if diags.HasErrors() || call.Name != "optional" {
+		typ, err := d.decode(expr)
+		if err != nil {
+			return nil, err
+		}
+		attr.Type = typ
+		return attr, nil
+	}
+
+	const maxOptionalArgs = 2
+	if len(call.Arguments) == 0 || len(call.Arguments) > maxOptionalArgs {
+		return nil, fmt.Errorf("%s: optional requires a type argument and an optional default value", call.ArgsRange)
+	}
+
+	typ, err := d.decode(call.Arguments[0])
+	if err != nil {
+		return nil, err
+	}
+	attr.Type = typ
+	attr.Optional = true
+
+	if len(call.Arguments) == maxOptionalArgs {
+		val, valDiags := call.Arguments[1].Value(nil)
+		if valDiags.HasErrors() {
+			return nil, fmt.Errorf("%s: invalid default value for %s: %w", call.Arguments[1].Range(), name, valDiags)
+		}
+		attr.HasDefault = true
+		attr.Default = hclparse.ExtractCtyValue(val)
+	}
+
+	return attr, nil
+}
@@ -0,0 +1,365 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// ValueError is an example or default that does not conform to the type of its node.
+type ValueError struct {
+	Path    string // Dotted path of the node, starting with the variable name
+	Field   string // The offending field, "example" or "default"
+	Message string
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.Path, e.Field, e.Message)
+}
+
+// ValidateValues type-checks the examples and defaults of a schema against the type of their node,
+// converting them like Terraform converts variable values. Objects must not have attributes the
+// type doesn't declare. Nodes without type information accept any value. Errors are returned in
+// render order.
+func ValidateValues(s *Schema) []*ValueError {
+	var errs []*ValueError
+
+	rootType := variableType(s)
+	if s.Marinate != nil {
+		errs = append(errs, validateInfo(s.Variable, s.Marinate, rootType)...)
+	}
+	if s.Default != nil {
+		if msg := checkValue(s.Default, rootType); msg != "" {
+			errs = append(errs, &ValueError{Path: s.Variable, Field: "default", Message: msg})
+		}
+	}
+
+	return append(errs, validateNodes(s.Variable, s.SchemaNodes)...)
+}
+
+// variableType returns the type of a variable's values. The root of an object variable is the
+// object itself; all other variables describe it in _marinate.
+func variableType(s *Schema) cty.Type {
+	switch {
+	case s.Marinate != nil:
+		return nodeType(s.Marinate, s.SchemaNodes)
+	case len(s.SchemaNodes) > 0:
+		return objectType(s.SchemaNodes)
+	default:
+		return cty.DynamicPseudoType
+	}
+}
+
+func validateNodes(prefix string, nodes map[string]*Node) []*ValueError {
+	var errs []*ValueError
+	for _, name := range SortedAttributeNames(nodes) {
+		node := nodes[name]
+		if node == nil {
+			continue
+		}
+		path := prefix + "." + name
+		if node.Marinate != nil {
+			errs = append(errs, validateInfo(path, node.Marinate, nodeType(node.Marinate, node.Attributes))...)
+		}
+		errs = append(errs, validateNodes(path, node.Attributes)...)
+	}
+	return errs
+}
+
+func validateInfo(path string, info *MarinateInfo, typ cty.Type) []*ValueError {
+	var errs []*ValueError
+	if info.Example != nil {
+		if msg := checkValue(info.Example, typ); msg != "" {
+			errs = append(errs, &ValueError{Path: path, Field: "example", Message: msg})
+		}
+	}
+	if info.Default != nil {
+		if msg := checkValue(info.Default, typ); msg != "" {
+			errs = append(errs, &ValueError{Path: path, Field: "default", Message: msg})
+		}
+	}
+	return errs
+}
+
+// ValueProblem is a part of a value that does not conform to its type.
+type ValueProblem struct {
+	Path    cty.Path // Path of the offending part within the value; empty for the value itself
+	Message string
+}
+
+func (p *ValueProblem) Error() string {
+	if len(p.Path) == 0 {
+		return p.Message
+	}
+	return formatCtyPath(p.Path) + ": " + p.Message
+}
+
+// CheckValue checks a variable value, as set in a tfvars file, against the type described by
+// the schema. Unlike conversion, it reports every problem: attributes the type does not declare,
+// missing required attributes and values that cannot be converted.
+func CheckValue(s *Schema, val cty.Value) []*ValueProblem {
+	return valueProblems(val, variableType(s), nil)
+}
+
+// checkValue returns why value does not conform to typ, or an empty string if it does.
+func checkValue(value any, typ cty.Type) string {
+	val, err := ctyValue(value)
+	if err != nil {
+		return err.Error()
+	}
+	if problems := valueProblems(val, typ, nil); len(problems) > 0 {
+		return problems[0].Error()
+	}
+	return ""
+}
+
+// valueProblems walks val along typ and reports every part that does not conform to it, in
+// attribute and element order. Objects must not have attributes typ doesn't declare: conversion
+// would silently drop them, but in documentation and tfvars files they are most likely typos.
+func valueProblems(val cty.Value, typ cty.Type, path cty.Path) []*ValueProblem {
+	if val.IsNull() || !val.IsKnown() || typ == cty.DynamicPseudoType {
+		return nil
+	}
+	valType := val.Type()
+	isCollection := valType.IsTupleType() || valType.IsListType() || valType.IsSetType()
+	isMapping := valType.IsObjectType() || valType.IsMapType()
+
+	switch {
+	case typ.IsObjectType() && isMapping:
+		return objectProblems(val, typ, path)
+	case (typ.IsListType() || typ.IsSetType()) && isCollection:
+		var problems []*ValueProblem
+		for i, elem := range elements(val) {
+			problems = append(problems, valueProblems(elem, typ.ElementType(), path.IndexInt(i))...)
+		}
+		return problems
+	case typ.IsTupleType() && isCollection && val.LengthInt() == len(typ.TupleElementTypes()):
+		elemTypes := typ.TupleElementTypes()
+		var problems []*ValueProblem
+		for i, elem := range elements(val) {
+			problems = append(problems, valueProblems(elem, elemTypes[i], path.IndexInt(i))...)
+		}
+		return problems
+	case typ.IsMapType() && isMapping:
+		attrs, names := attributes(val)
+		var problems []*ValueProblem
+		for _, name := range names {
+			problems = append(problems, valueProblems(attrs[name], typ.ElementType(), path.IndexString(name))...)
+		}
+		return problems
+	}
+
+	if _, err := convert.Convert(val, typ); err != nil {
+		var pathErr cty.PathError
+		if errors.As(err, &pathErr) {
+			return []*ValueProblem{{Path: append(path.Copy(), pathErr.Path...), Message: pathErr.Error()}}
+		}
+		return []*ValueProblem{{Path: path, Message: err.Error()}}
+	}
+	return nil
+}
+
+// objectProblems reports the unexpected and invalid attributes of val in name order,
+// followed by the required attributes it is missing.
+func objectProblems(val cty.Value, typ cty.Type, path cty.Path) []*ValueProblem {
+	attrs, names := attributes(val)
+
+	var problems []*ValueProblem
+	for _, name := range names {
+		if !typ.HasAttribute(name) {
+			problems = append(problems, &ValueProblem{
+				Path:    path.GetAttr(name),
+				Message: fmt.Sprintf("unexpected attribute %q", name),
+			})
+			continue
+		}
+		problems = append(problems, valueProblems(attrs[name], typ.AttributeType(name), path.GetAttr(name))...)
+	}
+
+	required := make([]string, 0, len(typ.AttributeTypes()))
+	for name := range typ.AttributeTypes() {
+		if _, ok := attrs[name]; !ok && !typ.AttributeOptional(name) {
+			required = append(required, name)
+		}
+	}
+	sort.Strings(required)
+	for _, name := range required {
+		problems = append(problems, &ValueProblem{Path: path, Message: fmt.Sprintf("attribute %q is required", name)})
+	}
+
+	return problems
+}
+
+// attributes returns the attributes of an object or map value and their sorted names.
+func attributes(val cty.Value) (map[string]cty.Value, []string) {
+	attrs := make(map[string]cty.Value, val.LengthInt())
+	names := make([]string, 0, val.LengthInt())
+	for it := val.ElementIterator(); it.Next(); {
+		key, elem := it.Element()
+		attrs[key.AsString()] = elem
+		names = append(names, key.AsString())
+	}
+	sort.Strings(names)
+	return attrs, names
+}
+
+// elements returns the elements of a tuple, list or set value.
+func elements(val cty.Value) []cty.Value {
+	elems := make([]cty.Value, 0, val.LengthInt())
+	for it := val.ElementIterator(); it.Next(); {
+		_, elem := it.Element()
+		elems = append(elems, elem)
+	}
+	return elems
+}
+
+// formatCtyPath formats a value path like HCL references it, e.g. tls.ciphers[0] or tags["env"].
+func formatCtyPath(path cty.Path) string {
+	var formatted string
+	for _, step := range path {
+		switch s := step.(type) {
+		case cty.GetAttrStep:
+			if formatted != "" {
+				formatted += "."
+			}
+			formatted += s.Name
+		case cty.IndexStep:
+			if s.Key.Type() == cty.String {
+				formatted += "[" + strconv.Quote(s.Key.AsString()) + "]"
+			} else if s.Key.Type() == cty.Number {
+				formatted += "[" + s.Key.AsBigFloat().Text('f', -1) + "]"
+			}
+		}
+	}
+	return formatted
+}
+
+// ctyValue converts a value decoded from YAML or HCL into a cty value. Lists become tuples and
+// maps become objects, so that conversion to the node's type decides what they are.
+func ctyValue(value any) (cty.Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return cty.NullVal(cty.DynamicPseudoType), nil
+	case string:
+		return cty.StringVal(v), nil
+	case bool:
+		return cty.BoolVal(v), nil
+	case int:
+		return cty.NumberIntVal(int64(v)), nil
+	case int64:
+		return cty.NumberIntVal(v), nil
+	case uint64:
+		return cty.NumberUIntVal(v), nil
+	case float64:
+		return cty.NumberFloatVal(v), nil
+	case []any:
+		elems := make([]cty.Value, len(v))
+		for i, elem := range v {
+			converted, err := ctyValue(elem)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			elems[i] = converted
+		}
+		return cty.TupleVal(elems), nil
+	case map[string]any:
+		attrs := make(map[string]cty.Value, len(v))
+		for key, elem := range v {
+			converted, err := ctyValue(elem)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			attrs[key] = converted
+		}
+		return cty.ObjectVal(attrs), nil
+	case map[any]any:
+		return ctyValue(StringKeys(v))
+	default:
+		return cty.NilVal, fmt.Errorf("unsupported value of type %T", value)
+	}
+}
+
+// nodeType returns the cty type a node describes. Parts of the type the schema does not
+// record, like the element type of a list of lists, are dynamic and accept any value.
+func nodeType(info *MarinateInfo, attrs map[string]*Node) cty.Type {
+	if info == nil {
+		return cty.DynamicPseudoType
+	}
+
+	switch info.Type {
+	case kindList:
+		return cty.List(elementType(info.ElementType, attrs))
+	case kindSet:
+		return cty.Set(elementType(info.ElementType, attrs))
+	case kindMap:
+		return cty.Map(elementType(info.ValueType, attrs))
+	default:
+		return elementType(info.Type, attrs)
+	}
+}
+
+// elementType returns the type of the given kind. Object and tuple structure comes from the
+// child nodes; nested maps are described by their _values child (see populateElementChildren).
+func elementType(kind string, attrs map[string]*Node) cty.Type {
+	switch kind {
+	case kindString:
+		return cty.String
+	case kindNumber:
+		return cty.Number
+	case kindBool:
+		return cty.Bool
+	case kindObject:
+		return objectType(attrs)
+	case kindTuple:
+		return tupleType(attrs)
+	case kindMap:
+		if values := attrs["_values"]; values != nil {
+			return nodeType(values.Marinate, values.Attributes)
+		}
+		return cty.Map(cty.DynamicPseudoType)
+	case kindList:
+		return cty.List(cty.DynamicPseudoType)
+	case kindSet:
+		return cty.Set(cty.DynamicPseudoType)
+	default:
+		return cty.DynamicPseudoType
+	}
+}
+
+// objectType returns the object type of the given attribute nodes. Attributes that are not
+// required are optional, so values may omit them.
+func objectType(attrs map[string]*Node) cty.Type {
+	types := make(map[string]cty.Type, len(attrs))
+	var optional []string
+	for name, node := range attrs {
+		if node == nil {
+			continue
+		}
+		types[name] = nodeType(node.Marinate, node.Attributes)
+		if node.Marinate == nil || !node.Marinate.Required {
+			optional = append(optional, name)
+		}
+	}
+	return cty.ObjectWithOptionalAttrs(types, optional)
+}
+
+// tupleType returns the tuple type of the positional child nodes _0, _1, ...
+func tupleType(attrs map[string]*Node) cty.Type {
+	var elems []cty.Type
+	for _, name := range SortedAttributeNames(attrs) {
+		if _, ok := TupleElementIndex(name); !ok {
+			continue
+		}
+		node := attrs[name]
+		if node == nil {
+			elems = append(elems, cty.DynamicPseudoType)
+			continue
+		}
+		elems = append(elems, nodeType(node.Marinate, node.Attributes))
+	}
+	return cty.Tuple(elems)
+}
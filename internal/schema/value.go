@@ -0,0 +1,35 @@
+package schema
+
+import "fmt"
+
+// JSONValue converts maps with non-string keys, as decoded from YAML, so the value can be
+// encoded as JSON.
+func JSONValue(value any) any {
+	switch v := value.(type) {
+	case []any:
+		converted := make([]any, len(v))
+		for i, elem := range v {
+			converted[i] = JSONValue(elem)
+		}
+		return converted
+	case map[string]any:
+		converted := make(map[string]any, len(v))
+		for key, elem := range v {
+			converted[key] = JSONValue(elem)
+		}
+		return converted
+	case map[any]any:
+		return JSONValue(StringKeys(v))
+	default:
+		return v
+	}
+}
+
+// StringKeys returns a copy of a map decoded from YAML with its keys formatted as strings.
+func StringKeys(m map[any]any) map[string]any {
+	converted := make(map[string]any, len(m))
+	for key, elem := range m {
+		converted[fmt.Sprintf("%v", key)] = elem
+	}
+	return converted
+}
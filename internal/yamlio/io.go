@@ -1,10 +1,15 @@
 package yamlio
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/glueckkanja/marinatemd/internal/logger"
 	"github.com/glueckkanja/marinatemd/internal/schema"
 	"gopkg.in/yaml.v3"
 )
@@ -34,8 +39,7 @@ func NewReader(exportPath string) *Reader {
 // ReadSchema reads a YAML schema file for the given variable name.
 // Returns nil, nil if the file doesn't exist (not an error condition).
 func (r *Reader) ReadSchema(variableName string) (*schema.Schema, error) {
-	// Construct path: {exportPath}/variables/{variableName}.yaml
-	yamlPath := filepath.Join(r.exportPath, "variables", variableName+".yaml")
+	yamlPath := r.SchemaPath(variableName)
 
 	// Check if file exists
 	if _, err := os.Stat(yamlPath); os.IsNotExist(err) {
@@ -58,9 +62,38 @@ func (r *Reader) ReadSchema(variableName string) (*schema.Schema, error) {
 	return &s, nil
 }
 
+// SchemaPath returns the path of the YAML schema file for the given variable name:
+// {exportPath}/variables/{variableName}.yaml.
+func (r *Reader) SchemaPath(variableName string) string {
+	return filepath.Join(r.exportPath, "variables", variableName+".yaml")
+}
+
+// ListSchemas returns the variable names of all YAML schema files in {exportPath}/variables, sorted.
+// Returns an empty list if the directory does not exist.
+func (r *Reader) ListSchemas() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(r.exportPath, "variables"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schema files: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".yaml" {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), ".yaml"))
+	}
+
+	// os.ReadDir returns entries sorted by file name, so names are already sorted
+	return names, nil
+}
+
 // SchemaExists checks if a YAML schema file exists for the given variable.
 func (r *Reader) SchemaExists(variableName string) (bool, error) {
-	yamlPath := filepath.Join(r.exportPath, "variables", variableName+".yaml")
+	yamlPath := r.SchemaPath(variableName)
 	_, err := os.Stat(yamlPath)
 	if os.IsNotExist(err) {
 		return false, nil
@@ -84,6 +117,7 @@ func NewWriter(exportPath string) *Writer {
 }
 
 // WriteSchema writes a schema to a YAML file.
+// An existing file is updated in place, see RenderSchema.
 func (w *Writer) WriteSchema(s *schema.Schema) error {
 	// Ensure export/variables/ directory exists
 	varDir := filepath.Join(w.exportPath, "variables")
@@ -93,17 +127,61 @@ func (w *Writer) WriteSchema(s *schema.Schema) error {
 
 	// Write to file: {exportPath}/variables/{schema.Variable}.yaml
 	yamlPath := filepath.Join(varDir, s.Variable+".yaml")
-	file, err := os.OpenFile(yamlPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	content, err := w.RenderSchema(s)
 	if err != nil {
-		return fmt.Errorf("failed to open YAML file %s for writing: %w", yamlPath, err)
+		return fmt.Errorf("failed to encode schema to YAML file %s: %w", yamlPath, err)
 	}
-	defer file.Close()
 
-	encoder := yaml.NewEncoder(file)
-	encoder.SetIndent(yamlIndentSize)
-	if err = encoder.Encode(s); err != nil {
-		return fmt.Errorf("failed to encode schema to YAML file %s: %w", yamlPath, err)
+	if writeErr := os.WriteFile(yamlPath, content, 0600); writeErr != nil {
+		return fmt.Errorf("failed to write YAML file %s: %w", yamlPath, writeErr)
 	}
 
 	return nil
 }
+
+// RenderSchema returns the content WriteSchema writes for a schema.
+//
+// If a YAML file for the variable already exists, only the entries whose values changed are
+// rewritten; comments, blank lines, key order and formatting of everything else are kept as they are.
+// Files that cannot be updated in place (e.g. flow-style documents) are re-encoded with EncodeSchema,
+// with a warning as their comments are lost.
+func (w *Writer) RenderSchema(s *schema.Schema) ([]byte, error) {
+	yamlPath := filepath.Join(w.exportPath, "variables", s.Variable+".yaml")
+	existing, err := os.ReadFile(yamlPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return EncodeSchema(s)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read YAML file %s: %w", yamlPath, err)
+	}
+
+	var desired yaml.Node
+	if encodeErr := desired.Encode(s); encodeErr != nil {
+		return nil, fmt.Errorf("failed to encode schema: %w", encodeErr)
+	}
+
+	patched, err := patchDocument(existing, &desired)
+	if err != nil {
+		// Re-encoding drops comments and formatting, which users must hear about
+		logger.Log.Warn("cannot update YAML file in place, re-encoding it without comments and formatting",
+			"path", yamlPath, "reason", err)
+		return EncodeSchema(s)
+	}
+
+	return patched, nil
+}
+
+// EncodeSchema returns the YAML document for a schema, as written for new files.
+func EncodeSchema(s *schema.Schema) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(yamlIndentSize)
+	if err := encoder.Encode(s); err != nil {
+		return nil, fmt.Errorf("failed to encode schema: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode schema: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,317 @@
+package yamlio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// lineEdit replaces the lines [start, end) of a document with lines.
+// An insertion has start == end.
+type lineEdit struct {
+	start int
+	end   int
+	lines []string
+}
+
+// patcher computes line edits that turn an existing YAML document into a desired one.
+type patcher struct {
+	lines []string
+	edits []lineEdit
+}
+
+// patchDocument updates the YAML document src so it holds the same data as desired.
+// Only the entries whose values changed are rewritten; comments, key order and formatting
+// of all other lines are kept byte-for-byte. New keys are inserted after their preceding
+// sibling in desired. Returns an error if src cannot be patched (e.g. it is not a block mapping).
+func patchDocument(src []byte, desired *yaml.Node) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse existing document: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 {
+		return nil, errors.New("existing document is empty")
+	}
+	existing := doc.Content[0]
+	if !isBlockMapping(existing) || desired.Kind != yaml.MappingNode {
+		return nil, errors.New("existing document is not a block mapping")
+	}
+
+	text := string(src)
+	trailingNewline := strings.HasSuffix(text, "\n")
+	p := &patcher{lines: strings.Split(strings.TrimSuffix(text, "\n"), "\n")}
+	if err := p.patchMapping(existing, desired, 0); err != nil {
+		return nil, err
+	}
+
+	result := strings.Join(p.apply(), "\n")
+	if trailingNewline {
+		result += "\n"
+	}
+
+	// The line-based edits rely on the block layout of the document; make sure the
+	// result still means exactly what was intended before using it.
+	if !sameData([]byte(result), desired) {
+		return nil, errors.New("patched document does not match the desired content")
+	}
+
+	return []byte(result), nil
+}
+
+// patchMapping records the edits that turn the block mapping existing into desired.
+// floor is the first line that may belong to the mapping's first entry.
+func (p *patcher) patchMapping(existing, desired *yaml.Node, floor int) error {
+	existingIndex := make(map[string]int, len(existing.Content)/2)
+	for i := 0; i+1 < len(existing.Content); i += 2 {
+		existingIndex[existing.Content[i].Value] = i
+	}
+	desiredKeys := make(map[string]bool, len(desired.Content)/2)
+	for i := 0; i+1 < len(desired.Content); i += 2 {
+		desiredKeys[desired.Content[i].Value] = true
+	}
+
+	indent := existing.Content[0].Column - 1
+
+	// New keys before the first kept key go above the first entry's head comment
+	insertAt := p.entryStart(existing.Content[0].Line-1, floor)
+
+	for i := 0; i+1 < len(desired.Content); i += 2 {
+		key, value := desired.Content[i], desired.Content[i+1]
+
+		idx, ok := existingIndex[key.Value]
+		if !ok {
+			lines, err := renderEntry(key, value, indent)
+			if err != nil {
+				return err
+			}
+			p.insert(insertAt, lines)
+			continue
+		}
+
+		existingKey, existingValue := existing.Content[idx], existing.Content[idx+1]
+		keyLine := existingKey.Line - 1
+		end := p.entryEnd(keyLine, indent, existingValue)
+		insertAt = end
+
+		if sameNode(existingValue, value) {
+			continue
+		}
+
+		if isBlockMapping(existingValue) && value.Kind == yaml.MappingNode && len(value.Content) > 0 {
+			if err := p.patchMapping(existingValue, value, keyLine+1); err != nil {
+				return err
+			}
+			continue
+		}
+
+		lines, err := renderEntry(keepKeyStyle(existingKey), keepLineComment(existingValue, value), indent)
+		if err != nil {
+			return err
+		}
+		p.edits = append(p.edits, lineEdit{start: keyLine, end: end, lines: lines})
+	}
+
+	// Remove entries that no longer exist, together with their head comments
+	prevEnd := floor
+	for i := 0; i+1 < len(existing.Content); i += 2 {
+		keyLine := existing.Content[i].Line - 1
+		end := p.entryEnd(keyLine, indent, existing.Content[i+1])
+		if !desiredKeys[existing.Content[i].Value] {
+			start, removeEnd := p.entryStart(keyLine, prevEnd), end
+			if i == 0 && !p.insertsAt(start) {
+				// Don't leave the blank lines that separated the first entry from the next one behind
+				for removeEnd < len(p.lines) && strings.TrimSpace(p.lines[removeEnd]) == "" {
+					removeEnd++
+				}
+			}
+			p.edits = append(p.edits, lineEdit{start: start, end: removeEnd})
+		}
+		prevEnd = end
+	}
+
+	return nil
+}
+
+// insert records an insertion, appending to a previous insertion at the same line to keep key order.
+func (p *patcher) insert(at int, lines []string) {
+	if n := len(p.edits); n > 0 {
+		last := &p.edits[n-1]
+		if last.start == at && last.end == at {
+			last.lines = append(last.lines, lines...)
+			return
+		}
+	}
+	p.edits = append(p.edits, lineEdit{start: at, end: at, lines: lines})
+}
+
+// insertsAt reports whether lines are inserted at line at.
+func (p *patcher) insertsAt(at int) bool {
+	for _, edit := range p.edits {
+		if edit.start == at && edit.end == at {
+			return true
+		}
+	}
+	return false
+}
+
+// apply returns the document lines with all recorded edits applied.
+func (p *patcher) apply() []string {
+	// Apply from the bottom up so earlier line numbers stay valid. At the same start line,
+	// removals and replacements go before insertions.
+	sort.SliceStable(p.edits, func(i, j int) bool {
+		if p.edits[i].start != p.edits[j].start {
+			return p.edits[i].start > p.edits[j].start
+		}
+		return p.edits[i].end > p.edits[j].end
+	})
+
+	lines := p.lines
+	for _, edit := range p.edits {
+		updated := make([]string, 0, len(lines)-(edit.end-edit.start)+len(edit.lines))
+		updated = append(updated, lines[:edit.start]...)
+		updated = append(updated, edit.lines...)
+		updated = append(updated, lines[edit.end:]...)
+		lines = updated
+	}
+	return lines
+}
+
+// entryEnd returns the line after the last content line of the mapping entry whose key is on keyLine.
+// An entry continues while lines are indented deeper than its key, or are sequence items at the key's
+// indentation. Trailing blank lines and comments are left to whatever follows, except in a block
+// scalar at the end of value: its lines are content even if they look like comments.
+func (p *patcher) entryEnd(keyLine, indent int, value *yaml.Node) int {
+	end := keyLine + 1
+	for i := keyLine + 1; i < len(p.lines); i++ {
+		trimmed := strings.TrimSpace(p.lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		lineIndent := lineIndentation(p.lines[i])
+		if lineIndent > indent || (lineIndent == indent && strings.HasPrefix(trimmed, "- ")) {
+			end = i + 1
+			continue
+		}
+		break
+	}
+
+	if scalar, scalarIndent := lastBlockScalar(value, indent); scalar != nil {
+		// A block scalar ends at the first non-blank line that is not indented deeper than its key
+		for i := scalar.Line; i < len(p.lines); i++ {
+			if strings.TrimSpace(p.lines[i]) == "" {
+				continue
+			}
+			if lineIndentation(p.lines[i]) <= scalarIndent {
+				break
+			}
+			end = max(end, i+1)
+		}
+	}
+	return end
+}
+
+// lastBlockScalar returns the literal or folded scalar that node ends with, if any, together with
+// the indentation of the key or sequence item it belongs to. indent is the indentation of node's key.
+func lastBlockScalar(node *yaml.Node, indent int) (*yaml.Node, int) {
+	switch {
+	case node.Kind == yaml.ScalarNode && node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0:
+		return node, indent
+	case isBlockMapping(node):
+		key := node.Content[len(node.Content)-2]
+		return lastBlockScalar(node.Content[len(node.Content)-1], key.Column-1)
+	case node.Kind == yaml.SequenceNode && node.Style&yaml.FlowStyle == 0 && len(node.Content) > 0:
+		return lastBlockScalar(node.Content[len(node.Content)-1], node.Column-1)
+	default:
+		return nil, 0
+	}
+}
+
+func lineIndentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// entryStart returns the first line of the comment block directly above keyLine, not going above floor.
+func (p *patcher) entryStart(keyLine, floor int) int {
+	start := keyLine
+	for i := keyLine - 1; i >= floor; i-- {
+		if !strings.HasPrefix(strings.TrimSpace(p.lines[i]), "#") {
+			break
+		}
+		start = i
+	}
+	return start
+}
+
+// renderEntry renders a single mapping entry indented by indent spaces.
+func renderEntry(key, value *yaml.Node, indent int) ([]string, error) {
+	entry := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{key, value}}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(yamlIndentSize)
+	if err := encoder.Encode(entry); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", key.Value, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", key.Value, err)
+	}
+
+	pad := strings.Repeat(" ", indent)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return lines, nil
+}
+
+// keepKeyStyle returns a copy of an existing key for re-rendering its entry.
+// The head comment is dropped because it stays in place above the rewritten lines.
+func keepKeyStyle(key *yaml.Node) *yaml.Node {
+	return &yaml.Node{
+		Kind:        key.Kind,
+		Style:       key.Style,
+		Tag:         key.Tag,
+		Value:       key.Value,
+		LineComment: key.LineComment,
+	}
+}
+
+// keepLineComment carries the line comment of an existing scalar value over to its replacement.
+func keepLineComment(existing, desired *yaml.Node) *yaml.Node {
+	if existing.LineComment == "" || desired.Kind != yaml.ScalarNode {
+		return desired
+	}
+	replacement := *desired
+	replacement.LineComment = existing.LineComment
+	return &replacement
+}
+
+func isBlockMapping(node *yaml.Node) bool {
+	return node.Kind == yaml.MappingNode && node.Style&yaml.FlowStyle == 0 && len(node.Content) > 0
+}
+
+// sameNode reports whether two nodes hold the same data, ignoring style and comments.
+func sameNode(a, b *yaml.Node) bool {
+	var av, bv any
+	if a.Decode(&av) != nil || b.Decode(&bv) != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
+
+// sameData reports whether the YAML document src holds the same data as node.
+func sameData(src []byte, node *yaml.Node) bool {
+	var got, want any
+	if yaml.Unmarshal(src, &got) != nil || node.Decode(&want) != nil {
+		return false
+	}
+	return reflect.DeepEqual(got, want)
+}
@@ -0,0 +1,223 @@
+package yamlio_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/glueckkanja/marinatemd/internal/schema"
+	"github.com/glueckkanja/marinatemd/internal/yamlio"
+)
+
+// existingYAML is a hand-edited schema file: comments, blank lines and
+// attributes in a custom (non-alphabetical) order.
+const existingYAML = `# Application settings
+variable: app
+version: "1"
+schema:
+  # Name first, it is what people look for
+  name:
+    _marinate:
+      description: The application name # keep short
+      type: string
+      required: true
+
+  tags:
+    _marinate:
+      description: |-
+        Tags applied to all resources.
+        Merged with the provider default tags.
+      type: map(string)
+`
+
+func writeExisting(t *testing.T, dir, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "variables", "app.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatalf("failed to create variables dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write existing schema: %v", err)
+	}
+	return path
+}
+
+func appSchema() *schema.Schema {
+	return &schema.Schema{
+		Variable: "app",
+		Version:  "1",
+		SchemaNodes: map[string]*schema.Node{
+			"name": {
+				Marinate: &schema.MarinateInfo{
+					Description: "The application name",
+					Type:        "string",
+					Required:    true,
+				},
+				Attributes: map[string]*schema.Node{},
+			},
+			"tags": {
+				Marinate: &schema.MarinateInfo{
+					Description: "Tags applied to all resources.\nMerged with the provider default tags.",
+					Type:        "map(string)",
+				},
+				Attributes: map[string]*schema.Node{},
+			},
+		},
+	}
+}
+
+func TestWriter_RenderSchema_Unchanged(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeExisting(t, tmpDir, existingYAML)
+
+	got, err := yamlio.NewWriter(tmpDir).RenderSchema(appSchema())
+	if err != nil {
+		t.Fatalf("RenderSchema() error = %v", err)
+	}
+
+	if string(got) != existingYAML {
+		t.Errorf("RenderSchema() changed an up-to-date file:\n%s", got)
+	}
+}
+
+func TestWriter_RenderSchema_PreservesCommentsAndOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeExisting(t, tmpDir, existingYAML)
+
+	s := appSchema()
+	s.SchemaNodes["name"].Marinate.Type = "list(string)"
+	s.SchemaNodes["enabled"] = &schema.Node{
+		Marinate: &schema.MarinateInfo{
+			Description: "# TODO: Add description for enabled",
+			Type:        "bool",
+		},
+		Attributes: map[string]*schema.Node{},
+	}
+
+	got, err := yamlio.NewWriter(tmpDir).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("RenderSchema() error = %v", err)
+	}
+
+	want := `# Application settings
+variable: app
+version: "1"
+schema:
+  enabled:
+    _marinate:
+      description: '# TODO: Add description for enabled'
+      type: bool
+  # Name first, it is what people look for
+  name:
+    _marinate:
+      description: The application name # keep short
+      type: list(string)
+      required: true
+
+  tags:
+    _marinate:
+      description: |-
+        Tags applied to all resources.
+        Merged with the provider default tags.
+      type: map(string)
+`
+	if string(got) != want {
+		t.Errorf("RenderSchema() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriter_RenderSchema_RemovesAttribute(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeExisting(t, tmpDir, existingYAML)
+
+	s := appSchema()
+	delete(s.SchemaNodes, "name")
+
+	got, err := yamlio.NewWriter(tmpDir).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("RenderSchema() error = %v", err)
+	}
+
+	want := `# Application settings
+variable: app
+version: "1"
+schema:
+  tags:
+    _marinate:
+      description: |-
+        Tags applied to all resources.
+        Merged with the provider default tags.
+      type: map(string)
+`
+	if string(got) != want {
+		t.Errorf("RenderSchema() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriter_RenderSchema_FallsBackForFlowStyle(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeExisting(t, tmpDir, `{variable: app, version: "1", schema: {}}`)
+
+	s := appSchema()
+	got, err := yamlio.NewWriter(tmpDir).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("RenderSchema() error = %v", err)
+	}
+
+	want, err := yamlio.EncodeSchema(s)
+	if err != nil {
+		t.Fatalf("EncodeSchema() error = %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("RenderSchema() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriter_RenderSchema_BlockScalarWithCommentLine(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeExisting(t, tmpDir, `variable: app
+version: "1"
+schema:
+  name:
+    _marinate:
+      description: |-
+        The application name.
+        # not a comment
+    # user comment
+  tags:
+    _marinate:
+      type: map(string)
+`)
+
+	s := appSchema()
+	s.SchemaNodes["name"].Marinate = &schema.MarinateInfo{
+		Description: "The application name.\n# not a comment",
+		NeedsReview: true,
+	}
+	s.SchemaNodes["tags"].Marinate.Description = ""
+
+	got, err := yamlio.NewWriter(tmpDir).RenderSchema(s)
+	if err != nil {
+		t.Fatalf("RenderSchema() error = %v", err)
+	}
+
+	// needs_review goes after the whole description, not into it
+	want := `variable: app
+version: "1"
+schema:
+  name:
+    _marinate:
+      description: |-
+        The application name.
+        # not a comment
+      needs_review: true
+    # user comment
+  tags:
+    _marinate:
+      type: map(string)
+`
+	if string(got) != want {
+		t.Errorf("RenderSchema() =\n%s\nwant\n%s", got, want)
+	}
+}